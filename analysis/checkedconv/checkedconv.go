@@ -0,0 +1,328 @@
+// Package checkedconv defines an analyzer reporting conversions of
+// non-constant integers into enumer types that are not checked with the
+// generated IsA method.
+package checkedconv
+
+import (
+	"bytes"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/token"
+	"go/types"
+	"strings"
+
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/analysis/passes/inspect"
+	"golang.org/x/tools/go/ast/inspector"
+
+	"github.com/capsule8/enumer/analysis/enumfacts"
+)
+
+const doc = `check conversions of integers into enumer types
+
+A conversion such as Day(n) of a non-constant integer creates values that are
+not declared, which String prints as "Day(42)". The conversion is reported
+unless the IsADay method checks the result before it is used:
+
+	Day(n).IsADay()
+	if Day(n).IsADay() { ... Day(n) ... }
+	d := Day(n)
+	if !d.IsADay() { ...; return }
+
+where the block rejecting the value ends with a return, a panic or an
+assignment of d. The suggested fix uses the DayFromInt function generated with -fromint.`
+
+// Analyzer reports unchecked conversions into enumer types.
+var Analyzer = &analysis.Analyzer{
+	Name:     "checkedconv",
+	Doc:      doc,
+	Run:      run,
+	Requires: []*analysis.Analyzer{inspect.Analyzer, enumfacts.Analyzer},
+}
+
+func run(pass *analysis.Pass) (interface{}, error) {
+	inspect := pass.ResultOf[inspect.Analyzer].(*inspector.Inspector)
+	enums := pass.ResultOf[enumfacts.Analyzer].(enumfacts.Result)
+
+	generated := make(map[*token.File]bool)
+	for _, file := range pass.Files {
+		if enumfacts.IsGenerated(file) {
+			generated[pass.Fset.File(file.Pos())] = true
+		}
+	}
+
+	inspect.WithStack([]ast.Node{(*ast.CallExpr)(nil)}, func(n ast.Node, push bool, stack []ast.Node) bool {
+		if !push {
+			return true
+		}
+		call := n.(*ast.CallExpr)
+		if len(call.Args) != 1 || generated[pass.Fset.File(call.Pos())] {
+			return true
+		}
+		fun, ok := pass.TypesInfo.Types[call.Fun]
+		if !ok || !fun.IsType() {
+			return true
+		}
+		tn, e := enums.Lookup(fun.Type)
+		if e == nil {
+			return true
+		}
+		arg := pass.TypesInfo.Types[call.Args[0]]
+		if arg.Value != nil || !isInteger(arg.Type) || types.Identical(arg.Type, fun.Type) {
+			return true
+		}
+		if checked(pass, call, tn, stack) {
+			return true
+		}
+
+		isA := "IsA" + tn.Name()
+		fromInt, _ := tn.Pkg().Scope().Lookup(tn.Name() + "FromInt").(*types.Func)
+		if fromInt == nil {
+			pass.Reportf(call.Pos(), "unchecked conversion of %s to %s; check the result with %s",
+				types.ExprString(call.Args[0]), types.ExprString(call.Fun), isA)
+			return true
+		}
+		d := analysis.Diagnostic{
+			Pos:     call.Pos(),
+			End:     call.End(),
+			Message: fmt.Sprintf("unchecked conversion of %s to %s; use %s", types.ExprString(call.Args[0]), types.ExprString(call.Fun), fromInt.Name()),
+		}
+		if fix, ok := suggestFix(pass, call, fromInt, stack); ok {
+			d.SuggestedFixes = []analysis.SuggestedFix{fix}
+		}
+		pass.Report(d)
+		return true
+	})
+	return nil, nil
+}
+
+// isInteger reports whether t is an integer type.
+func isInteger(t types.Type) bool {
+	basic, ok := t.Underlying().(*types.Basic)
+	return ok && basic.Info()&types.IsInteger != 0
+}
+
+// checked reports whether the conversion at the top of the stack is checked
+// by the IsA method of its type.
+func checked(pass *analysis.Pass, conv *ast.CallExpr, tn *types.TypeName, stack []ast.Node) bool {
+	isA := "IsA" + tn.Name()
+	// Day(n).IsADay()
+	if sel, ok := stack[len(stack)-2].(*ast.SelectorExpr); ok && sel.X == conv && sel.Sel.Name == isA {
+		return true
+	}
+	// if Day(n).IsADay() { ... Day(n) ... }
+	convString := types.ExprString(conv)
+	for i := len(stack) - 2; i > 0; i-- {
+		ifStmt, ok := stack[i].(*ast.IfStmt)
+		if !ok || stack[i+1] != ifStmt.Body {
+			continue
+		}
+		if callsIsA(ifStmt.Cond, isA, func(x ast.Expr) bool { return types.ExprString(x) == convString }) {
+			return true
+		}
+	}
+	// d := Day(n); if !d.IsADay() { ... }
+	v := assignedVar(pass, conv, stack)
+	if v == nil {
+		return false
+	}
+	for i := len(stack) - 2; i >= 0; i-- {
+		block, ok := stack[i].(*ast.BlockStmt)
+		if !ok {
+			continue
+		}
+		after := false
+		for _, stmt := range block.List {
+			if stmt == stack[i+1] {
+				after = true
+				continue
+			}
+			if !after || !uses(pass, stmt, v) {
+				continue
+			}
+			// The first statement using the variable must check it, and
+			// not let an invalid value through.
+			ifStmt, ok := stmt.(*ast.IfStmt)
+			if !ok || ifStmt.Init != nil {
+				return false
+			}
+			not, ok := ast.Unparen(ifStmt.Cond).(*ast.UnaryExpr)
+			return ok && not.Op == token.NOT && callsIsA(not.X, isA, func(x ast.Expr) bool {
+				id, ok := x.(*ast.Ident)
+				return ok && pass.TypesInfo.Uses[id] == v
+			}) && rejects(pass, ifStmt.Body, v)
+		}
+		return false
+	}
+	return false
+}
+
+// rejects reports whether the block ends by returning, panicking or
+// assigning the variable, so that its invalid value is not used after it.
+func rejects(pass *analysis.Pass, block *ast.BlockStmt, v *types.Var) bool {
+	if len(block.List) == 0 {
+		return false
+	}
+	switch last := block.List[len(block.List)-1].(type) {
+	case *ast.ReturnStmt:
+		return true
+	case *ast.ExprStmt:
+		call, ok := ast.Unparen(last.X).(*ast.CallExpr)
+		if !ok {
+			return false
+		}
+		id, ok := ast.Unparen(call.Fun).(*ast.Ident)
+		return ok && pass.TypesInfo.Uses[id] == types.Universe.Lookup("panic")
+	case *ast.AssignStmt:
+		for _, lhs := range last.Lhs {
+			if id, ok := lhs.(*ast.Ident); ok && pass.TypesInfo.Uses[id] == v {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// callsIsA reports whether the expression calls the IsA method on a receiver
+// matching recv.
+func callsIsA(expr ast.Expr, isA string, recv func(ast.Expr) bool) bool {
+	found := false
+	ast.Inspect(expr, func(n ast.Node) bool {
+		if call, ok := n.(*ast.CallExpr); ok {
+			if sel, ok := call.Fun.(*ast.SelectorExpr); ok && sel.Sel.Name == isA && recv(sel.X) {
+				found = true
+			}
+		}
+		return !found
+	})
+	return found
+}
+
+// assignedVar returns the variable the conversion is assigned to, as in
+// "d := Day(n)", "d = Day(n)" or "var d = Day(n)".
+func assignedVar(pass *analysis.Pass, conv *ast.CallExpr, stack []ast.Node) *types.Var {
+	var lhs ast.Expr
+	switch parent := stack[len(stack)-2].(type) {
+	case *ast.AssignStmt:
+		if len(parent.Lhs) == 1 && len(parent.Rhs) == 1 {
+			lhs = parent.Lhs[0]
+		}
+	case *ast.ValueSpec:
+		if len(parent.Names) == 1 && len(parent.Values) == 1 {
+			lhs = parent.Names[0]
+		}
+	}
+	id, ok := lhs.(*ast.Ident)
+	if !ok {
+		return nil
+	}
+	obj := pass.TypesInfo.Defs[id]
+	if obj == nil {
+		obj = pass.TypesInfo.Uses[id]
+	}
+	v, _ := obj.(*types.Var)
+	return v
+}
+
+// uses reports whether the node refers to the variable.
+func uses(pass *analysis.Pass, n ast.Node, v *types.Var) bool {
+	found := false
+	ast.Inspect(n, func(n ast.Node) bool {
+		if id, ok := n.(*ast.Ident); ok && pass.TypesInfo.Uses[id] == v {
+			found = true
+		}
+		return !found
+	})
+	return found
+}
+
+// suggestFix rewrites "d := Day(n)" into a call of DayFromInt returning the
+// error, when the conversion is assigned to a new variable in a function
+// whose last result is an error.
+func suggestFix(pass *analysis.Pass, conv *ast.CallExpr, fromInt *types.Func, stack []ast.Node) (analysis.SuggestedFix, bool) {
+	assign, ok := stack[len(stack)-2].(*ast.AssignStmt)
+	if !ok || assign.Tok != token.DEFINE || len(assign.Lhs) != 1 || len(assign.Rhs) != 1 {
+		return analysis.SuggestedFix{}, false
+	}
+	if _, ok := stack[len(stack)-3].(*ast.BlockStmt); !ok {
+		return analysis.SuggestedFix{}, false
+	}
+	var sig *types.Signature
+	for i := len(stack) - 1; i >= 0 && sig == nil; i-- {
+		switch f := stack[i].(type) {
+		case *ast.FuncDecl:
+			sig, _ = pass.TypesInfo.Defs[f.Name].Type().(*types.Signature)
+		case *ast.FuncLit:
+			sig, _ = pass.TypesInfo.Types[f].Type.(*types.Signature)
+		}
+	}
+	if sig == nil || sig.Results().Len() == 0 {
+		return analysis.SuggestedFix{}, false
+	}
+	results := sig.Results()
+	if !types.Identical(results.At(results.Len()-1).Type(), types.Universe.Lookup("error").Type()) {
+		return analysis.SuggestedFix{}, false
+	}
+	qualifier := func(p *types.Package) string {
+		if p == pass.Pkg {
+			return ""
+		}
+		return p.Name()
+	}
+	zeros := make([]string, 0, results.Len())
+	for i := 0; i < results.Len()-1; i++ {
+		zero, ok := zeroValue(results.At(i).Type(), qualifier)
+		if !ok {
+			return analysis.SuggestedFix{}, false
+		}
+		zeros = append(zeros, zero)
+	}
+	zeros = append(zeros, "err")
+
+	fun := fromInt.Name()
+	if sel, ok := conv.Fun.(*ast.SelectorExpr); ok {
+		fun = types.ExprString(sel.X) + "." + fun
+	}
+	arg := types.ExprString(conv.Args[0])
+	if !types.Identical(pass.TypesInfo.TypeOf(conv.Args[0]), types.Typ[types.Int]) {
+		arg = "int(" + arg + ")"
+	}
+	var b bytes.Buffer
+	fmt.Fprintf(&b, "%s, err := %s(%s)\nif err != nil {\n\treturn %s\n}", types.ExprString(assign.Lhs[0]), fun, arg, strings.Join(zeros, ", "))
+	src, err := format.Source(b.Bytes())
+	if err != nil {
+		return analysis.SuggestedFix{}, false
+	}
+	return analysis.SuggestedFix{
+		Message: "Use " + fromInt.Name(),
+		TextEdits: []analysis.TextEdit{{
+			Pos:     assign.Pos(),
+			End:     assign.End(),
+			NewText: src,
+		}},
+	}, true
+}
+
+// zeroValue returns the zero value of t as Go source.
+func zeroValue(t types.Type, qualifier types.Qualifier) (string, bool) {
+	if _, ok := t.(*types.TypeParam); ok {
+		return "*new(" + types.TypeString(t, qualifier) + ")", true
+	}
+	switch u := t.Underlying().(type) {
+	case *types.Basic:
+		switch {
+		case u.Info()&types.IsBoolean != 0:
+			return "false", true
+		case u.Info()&types.IsString != 0:
+			return `""`, true
+		case u.Info()&types.IsNumeric != 0:
+			return "0", true
+		}
+	case *types.Pointer, *types.Slice, *types.Map, *types.Chan, *types.Signature, *types.Interface:
+		return "nil", true
+	case *types.Struct, *types.Array:
+		return types.TypeString(t, qualifier) + "{}", true
+	}
+	return "", false
+}
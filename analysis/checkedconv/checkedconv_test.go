@@ -0,0 +1,14 @@
+package checkedconv_test
+
+import (
+	"testing"
+
+	"golang.org/x/tools/go/analysis/analysistest"
+
+	"github.com/capsule8/enumer/analysis/checkedconv"
+)
+
+func TestAnalyzer(t *testing.T) {
+	testdata := analysistest.TestData()
+	analysistest.RunWithSuggestedFixes(t, testdata, checkedconv.Analyzer, "a")
+}
@@ -0,0 +1,90 @@
+package a
+
+import (
+	"errors"
+
+	"day"
+)
+
+type packet struct{ day, hour int32 }
+
+func decode(p packet) (day.Day, error) {
+	d := day.Day(p.day) // want `unchecked conversion of p.day to day.Day; use DayFromInt`
+	return d, nil
+}
+
+func decodeCustom(p packet) (*packet, string, error) {
+	d := day.Day(p.day) // want `unchecked conversion of p.day to day.Day; use DayFromInt`
+	_ = d
+	return nil, "", nil
+}
+
+func noError(n int) day.Day {
+	return day.Day(n) // want `unchecked conversion of n to day.Day; use DayFromInt`
+}
+
+func hour(n int) day.Hour {
+	return day.Hour(n) // want `unchecked conversion of n to day.Hour; check the result with IsAHour`
+}
+
+func checkedInline(n int) bool {
+	return day.Day(n).IsADay()
+}
+
+func checkedIf(n int) day.Day {
+	if n > 0 && day.Day(n).IsADay() {
+		return day.Day(n)
+	}
+	return day.Monday
+}
+
+func checkedAfter(n int) (day.Day, error) {
+	d := day.Day(n)
+	if !d.IsADay() {
+		return 0, errors.New("bad day")
+	}
+	return d, nil
+}
+
+func checkedPanic(n int) day.Day {
+	d := day.Day(n)
+	if !d.IsADay() {
+		panic("bad day")
+	}
+	return d
+}
+
+func checkedDefault(n int) day.Day {
+	d := day.Day(n)
+	if !d.IsADay() {
+		println("bad day", n)
+		d = day.Monday
+	}
+	return d
+}
+
+func logOnly(n int) day.Day {
+	d := day.Day(n) // want `unchecked conversion of n to day.Day; use DayFromInt`
+	if !d.IsADay() {
+		println("bad day", n)
+	}
+	return d
+}
+
+func usedBeforeCheck(n int) (day.Day, error) {
+	var d = day.Day(n) // want `unchecked conversion of n to day.Day; use DayFromInt`
+	println(d.String())
+	if !d.IsADay() {
+		return 0, errors.New("bad day")
+	}
+	return d, nil
+}
+
+func constant() day.Day {
+	const n = 2
+	return day.Day(n) + day.Day(1)
+}
+
+func notInteger(d day.Day) day.Day {
+	return day.Day(d)
+}
@@ -0,0 +1,17 @@
+package day
+
+type Day uint8
+
+const (
+	Monday Day = iota
+	Tuesday
+	Wednesday
+)
+
+// Hour has no FromInt function.
+type Hour int
+
+const (
+	Noon     Hour = 12
+	Midnight Hour = 0
+)
@@ -0,0 +1,301 @@
+// Package enumfacts defines an analyzer recognizing the types with enumer
+// output, for the analyzers checking their use.
+//
+// A type is an enumer type if its package holds a file generated by enumer
+// declaring its list of values. The analyzer exports an Enum fact for each, so
+// that the types are recognized in dependent packages as well.
+package enumfacts
+
+import (
+	"go/ast"
+	"go/constant"
+	"go/token"
+	"go/types"
+	"reflect"
+	"sort"
+	"strings"
+
+	"golang.org/x/tools/go/analysis"
+)
+
+const doc = `recognize the types with enumer output
+
+The result is a map from the type names of the enumer types of the package
+and of its dependencies to their values.`
+
+// Analyzer recognizes enumer types.
+var Analyzer = &analysis.Analyzer{
+	Name:       "enumfacts",
+	Doc:        doc,
+	Run:        run,
+	FactTypes:  []analysis.Fact{new(Enum)},
+	ResultType: reflect.TypeOf(Result(nil)),
+}
+
+// Result maps the enumer types visible to a package to their values.
+type Result map[*types.TypeName]*Enum
+
+// Enum is the fact describing an enumer type.
+type Enum struct {
+	// Values lists the values of the type XValues returns, in order.
+	Values []Value
+	// Constants lists all the constants of the type, including the aliases
+	// and the deprecated ones, in order of declaration.
+	Constants []Constant
+}
+
+// Value is a value listed by XValues.
+type Value struct {
+	Value string // The exact value, as given by constant.Value.ExactString.
+	Name  string // The name of the first constant with the value.
+}
+
+// Constant is a constant of an enumer type.
+type Constant struct {
+	Name       string
+	Value      string // The exact value, as given by constant.Value.ExactString.
+	String     string // The name String returns for the value, from the generated tables.
+	Deprecated bool   // Whether its doc comment has a "Deprecated:" paragraph.
+}
+
+func (*Enum) AFact() {}
+
+func (e *Enum) String() string {
+	names := make([]string, len(e.Values))
+	for i, v := range e.Values {
+		names[i] = v.Name
+	}
+	return "enum(" + strings.Join(names, ", ") + ")"
+}
+
+// ConstantsNamed returns the constants whose value String prints as s,
+// the ones not deprecated first.
+func (e *Enum) ConstantsNamed(s string) []Constant {
+	var consts, deprecated []Constant
+	for _, c := range e.Constants {
+		switch {
+		case c.String != s:
+		case c.Deprecated:
+			deprecated = append(deprecated, c)
+		default:
+			consts = append(consts, c)
+		}
+	}
+	return append(consts, deprecated...)
+}
+
+// IsGenerated reports whether the file was generated by enumer.
+func IsGenerated(file *ast.File) bool {
+	for _, group := range file.Comments {
+		if group.Pos() >= file.Package {
+			break
+		}
+		for _, c := range group.List {
+			if strings.HasPrefix(c.Text, `// Code generated by "enumer`) && strings.HasSuffix(c.Text, "DO NOT EDIT.") {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func run(pass *analysis.Pass) (interface{}, error) {
+	result := make(Result)
+	for _, fact := range pass.AllObjectFacts() {
+		if tn, ok := fact.Object.(*types.TypeName); ok {
+			result[tn] = fact.Fact.(*Enum)
+		}
+	}
+
+	docs := constantDocs(pass.Files)
+	for _, file := range pass.Files {
+		if !IsGenerated(file) {
+			continue
+		}
+		for _, decl := range file.Decls {
+			decl, ok := decl.(*ast.GenDecl)
+			if !ok || decl.Tok != token.VAR {
+				continue
+			}
+			for _, spec := range decl.Specs {
+				vspec := spec.(*ast.ValueSpec)
+				if len(vspec.Names) != 1 || len(vspec.Values) != 1 {
+					continue
+				}
+				lit, ok := vspec.Values[0].(*ast.CompositeLit)
+				if !ok {
+					continue
+				}
+				slice, ok := pass.TypesInfo.TypeOf(lit).(*types.Slice)
+				if !ok {
+					continue
+				}
+				tn := typeName(slice.Elem())
+				if tn == nil || tn.Pkg() != pass.Pkg || vspec.Names[0].Name != "_"+tn.Name()+"Values" {
+					continue
+				}
+				e := newEnum(pass, tn, lit, docs)
+				e.addStrings(pass, file, tn)
+				pass.ExportObjectFact(tn, e)
+				result[tn] = e
+			}
+		}
+	}
+	return result, nil
+}
+
+// newEnum describes the type from the elements of its _XValues slice and its
+// constants.
+func newEnum(pass *analysis.Pass, tn *types.TypeName, values *ast.CompositeLit, docs map[string]string) *Enum {
+	e := new(Enum)
+	var consts []*types.Const
+	scope := pass.Pkg.Scope()
+	for _, name := range scope.Names() {
+		if c, ok := scope.Lookup(name).(*types.Const); ok && types.Identical(c.Type(), tn.Type()) && name != "_" {
+			consts = append(consts, c)
+		}
+	}
+	sort.Slice(consts, func(i, j int) bool { return consts[i].Pos() < consts[j].Pos() })
+	for _, c := range consts {
+		e.Constants = append(e.Constants, Constant{
+			Name:       c.Name(),
+			Value:      c.Val().ExactString(),
+			Deprecated: isDeprecated(docs[c.Name()]),
+		})
+	}
+	for _, elt := range values.Elts {
+		tv, ok := pass.TypesInfo.Types[elt]
+		if !ok || tv.Value == nil || tv.Value.Kind() != constant.Int {
+			continue
+		}
+		v := Value{Value: tv.Value.ExactString()}
+		for _, c := range e.Constants {
+			if c.Value == v.Value {
+				v.Name = c.Name
+				break
+			}
+		}
+		e.Values = append(e.Values, v)
+	}
+	return e
+}
+
+// addStrings sets the String of the constants from the generated map from
+// value to name, e.g.
+//
+//	var _DayMap = map[Day]string{
+//		0: _DayName[0:6],
+//		...
+//	}
+func (e *Enum) addStrings(pass *analysis.Pass, file *ast.File, tn *types.TypeName) {
+	strs := make(map[string]string)
+	for _, decl := range file.Decls {
+		decl, ok := decl.(*ast.GenDecl)
+		if !ok || decl.Tok != token.VAR {
+			continue
+		}
+		for _, spec := range decl.Specs {
+			vspec := spec.(*ast.ValueSpec)
+			if len(vspec.Names) != 1 || len(vspec.Values) != 1 || vspec.Names[0].Name != "_"+tn.Name()+"Map" {
+				continue
+			}
+			table, ok := vspec.Values[0].(*ast.CompositeLit)
+			if !ok {
+				continue
+			}
+			for _, elt := range table.Elts {
+				kv, ok := elt.(*ast.KeyValueExpr)
+				if !ok {
+					continue
+				}
+				key := pass.TypesInfo.Types[kv.Key].Value
+				if s, ok := stringValue(pass, kv.Value); ok && key != nil {
+					strs[key.ExactString()] = s
+				}
+			}
+		}
+	}
+	for i, c := range e.Constants {
+		e.Constants[i].String = strs[c.Value]
+	}
+}
+
+// stringValue evaluates a string constant or a slice of one with constant
+// bounds, such as _DayName[0:6].
+func stringValue(pass *analysis.Pass, expr ast.Expr) (string, bool) {
+	if tv := pass.TypesInfo.Types[expr]; tv.Value != nil && tv.Value.Kind() == constant.String {
+		return constant.StringVal(tv.Value), true
+	}
+	slice, ok := expr.(*ast.SliceExpr)
+	if !ok || slice.Low == nil || slice.High == nil || slice.Slice3 {
+		return "", false
+	}
+	s, ok := stringValue(pass, slice.X)
+	low, lowOK := constant.Int64Val(constantOf(pass, slice.Low))
+	high, highOK := constant.Int64Val(constantOf(pass, slice.High))
+	if !ok || !lowOK || !highOK || low < 0 || low > high || high > int64(len(s)) {
+		return "", false
+	}
+	return s[low:high], true
+}
+
+// constantOf returns the value of a constant expression, or an unknown value.
+func constantOf(pass *analysis.Pass, expr ast.Expr) constant.Value {
+	if v := pass.TypesInfo.Types[expr].Value; v != nil {
+		return v
+	}
+	return constant.MakeUnknown()
+}
+
+// typeName returns the name of the named type t, if it is one.
+func typeName(t types.Type) *types.TypeName {
+	if named, ok := types.Unalias(t).(*types.Named); ok {
+		return named.Obj()
+	}
+	return nil
+}
+
+// Lookup returns the enumer type of t, if it is one.
+func (r Result) Lookup(t types.Type) (*types.TypeName, *Enum) {
+	tn := typeName(t)
+	if tn == nil {
+		return nil, nil
+	}
+	return tn, r[tn]
+}
+
+// constantDocs returns the doc comments of the package-level constants of
+// the files, by name.
+func constantDocs(files []*ast.File) map[string]string {
+	docs := make(map[string]string)
+	for _, file := range files {
+		for _, decl := range file.Decls {
+			decl, ok := decl.(*ast.GenDecl)
+			if !ok || decl.Tok != token.CONST {
+				continue
+			}
+			for _, spec := range decl.Specs {
+				vspec := spec.(*ast.ValueSpec)
+				doc := vspec.Doc
+				if doc == nil && !decl.Lparen.IsValid() {
+					doc = decl.Doc
+				}
+				for _, name := range vspec.Names {
+					docs[name.Name] = doc.Text()
+				}
+			}
+		}
+	}
+	return docs
+}
+
+// isDeprecated reports whether the doc comment has a paragraph starting with
+// "Deprecated:".
+func isDeprecated(doc string) bool {
+	for _, p := range strings.Split(doc, "\n\n") {
+		if strings.HasPrefix(p, "Deprecated:") {
+			return true
+		}
+	}
+	return false
+}
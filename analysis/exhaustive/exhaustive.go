@@ -0,0 +1,79 @@
+// Package exhaustive defines an analyzer reporting switch statements over
+// enumer types that miss some of their values.
+package exhaustive
+
+import (
+	"go/ast"
+	"strings"
+
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/analysis/passes/inspect"
+	"golang.org/x/tools/go/ast/inspector"
+
+	"github.com/capsule8/enumer/analysis/enumfacts"
+)
+
+const doc = `check that switch statements over enumer types are exhaustive
+
+A switch statement whose tag is of a type with enumer output must have a case
+for every value listed by the generated XValues function. Aliases cover the
+values they share, and deprecated values, which XValues leaves out, need no
+case. With -default-signifies-exhaustive, a default clause makes any switch
+exhaustive.`
+
+// Analyzer reports non-exhaustive switch statements over enumer types.
+var Analyzer = &analysis.Analyzer{
+	Name:     "exhaustive",
+	Doc:      doc,
+	Run:      run,
+	Requires: []*analysis.Analyzer{inspect.Analyzer, enumfacts.Analyzer},
+}
+
+var defaultSignifiesExhaustive bool
+
+func init() {
+	Analyzer.Flags.BoolVar(&defaultSignifiesExhaustive, "default-signifies-exhaustive", false,
+		"consider a switch with a default clause exhaustive")
+}
+
+func run(pass *analysis.Pass) (interface{}, error) {
+	inspect := pass.ResultOf[inspect.Analyzer].(*inspector.Inspector)
+	enums := pass.ResultOf[enumfacts.Analyzer].(enumfacts.Result)
+
+	inspect.Preorder([]ast.Node{(*ast.SwitchStmt)(nil)}, func(n ast.Node) {
+		sw := n.(*ast.SwitchStmt)
+		if sw.Tag == nil {
+			return
+		}
+		tn, e := enums.Lookup(pass.TypesInfo.TypeOf(sw.Tag))
+		if e == nil {
+			return
+		}
+		covered := make(map[string]bool)
+		hasDefault := false
+		for _, stmt := range sw.Body.List {
+			clause := stmt.(*ast.CaseClause)
+			if clause.List == nil {
+				hasDefault = true
+			}
+			for _, expr := range clause.List {
+				if tv, ok := pass.TypesInfo.Types[expr]; ok && tv.Value != nil {
+					covered[tv.Value.ExactString()] = true
+				}
+			}
+		}
+		if hasDefault && defaultSignifiesExhaustive {
+			return
+		}
+		var missing []string
+		for _, v := range e.Values {
+			if !covered[v.Value] {
+				missing = append(missing, v.Name)
+			}
+		}
+		if len(missing) > 0 {
+			pass.Reportf(sw.Pos(), "missing cases in switch of type %s.%s: %s", tn.Pkg().Name(), tn.Name(), strings.Join(missing, ", "))
+		}
+	})
+	return nil, nil
+}
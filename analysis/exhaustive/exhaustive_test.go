@@ -0,0 +1,23 @@
+package exhaustive_test
+
+import (
+	"testing"
+
+	"golang.org/x/tools/go/analysis/analysistest"
+
+	"github.com/capsule8/enumer/analysis/exhaustive"
+)
+
+func TestAnalyzer(t *testing.T) {
+	testdata := analysistest.TestData()
+	analysistest.Run(t, testdata, exhaustive.Analyzer, "a")
+}
+
+func TestDefaultSignifiesExhaustive(t *testing.T) {
+	testdata := analysistest.TestData()
+	if err := exhaustive.Analyzer.Flags.Set("default-signifies-exhaustive", "true"); err != nil {
+		t.Fatal(err)
+	}
+	defer exhaustive.Analyzer.Flags.Set("default-signifies-exhaustive", "false")
+	analysistest.Run(t, testdata, exhaustive.Analyzer, "b")
+}
@@ -0,0 +1,47 @@
+package a
+
+import "day"
+
+func full(d day.Day) {
+	switch d {
+	case day.Monday, day.Tuesday:
+	case day.Wednesday:
+	}
+}
+
+func alias(d day.Day) {
+	switch d { // Deprecated values need no case.
+	case day.Mon, day.Tuesday, day.Wednesday:
+	}
+}
+
+func partial(d day.Day) {
+	switch d { // want "missing cases in switch of type day.Day: Tuesday, Wednesday"
+	case day.Monday, day.Midweek:
+	}
+}
+
+func withDefault(d day.Day) {
+	switch d { // want "missing cases in switch of type day.Day: Monday"
+	case day.Tuesday, day.Wednesday:
+	default:
+	}
+}
+
+func local() {
+	switch d := day.Day(1); d { // want "missing cases in switch of type day.Day: Monday, Wednesday"
+	case day.Tuesday:
+	}
+}
+
+func plain(p day.Plain) {
+	switch p {
+	case day.PlainA:
+	}
+}
+
+func tagless(d day.Day) {
+	switch {
+	case d == day.Monday:
+	}
+}
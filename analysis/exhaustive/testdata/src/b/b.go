@@ -0,0 +1,16 @@
+package b
+
+import "day"
+
+func withDefault(d day.Day) {
+	switch d {
+	case day.Tuesday, day.Wednesday:
+	default:
+	}
+}
+
+func partial(d day.Day) {
+	switch d { // want "missing cases in switch of type day.Day: Monday"
+	case day.Tuesday, day.Wednesday:
+	}
+}
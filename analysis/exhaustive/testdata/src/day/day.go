@@ -0,0 +1,21 @@
+package day
+
+type Day int
+
+const (
+	Monday Day = iota
+	Tuesday
+	Wednesday
+	// Deprecated: use Wednesday.
+	Midweek
+	// Mon is an alias for Monday.
+	Mon = Monday
+)
+
+// Plain has no enumer output.
+type Plain int
+
+const (
+	PlainA Plain = iota
+	PlainB
+)
@@ -0,0 +1,159 @@
+// Package namecompare defines an analyzer reporting comparisons of the names
+// of enumer values with string literals.
+package namecompare
+
+import (
+	"fmt"
+	"go/ast"
+	"go/constant"
+	"go/token"
+	"go/types"
+	"strconv"
+
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/analysis/passes/inspect"
+	"golang.org/x/tools/go/ast/inspector"
+
+	"github.com/capsule8/enumer/analysis/enumfacts"
+)
+
+const doc = `check for comparisons of enum names with string literals
+
+Comparisons such as d.String() == "Monday", where d is of an enumer type and
+"Monday" the name of one of its values, break when the names change with
+-transform or -trimprefix. The suggested fix compares d with the constant
+instead.
+
+Comparisons such as string(d) == "Monday" are reported as bugs: the
+conversion yields the character whose code point is the value of d, not its
+name. No fix is suggested, as comparing d with the constant changes what the
+program does.`
+
+// Analyzer reports comparisons of enumer names with string literals.
+var Analyzer = &analysis.Analyzer{
+	Name:     "namecompare",
+	Doc:      doc,
+	Run:      run,
+	Requires: []*analysis.Analyzer{inspect.Analyzer, enumfacts.Analyzer},
+}
+
+func run(pass *analysis.Pass) (interface{}, error) {
+	inspect := pass.ResultOf[inspect.Analyzer].(*inspector.Inspector)
+	enums := pass.ResultOf[enumfacts.Analyzer].(enumfacts.Result)
+
+	var file *ast.File
+	inspect.Preorder([]ast.Node{(*ast.File)(nil), (*ast.BinaryExpr)(nil)}, func(n ast.Node) {
+		if f, ok := n.(*ast.File); ok {
+			file = f
+			return
+		}
+		cmp := n.(*ast.BinaryExpr)
+		if cmp.Op != token.EQL && cmp.Op != token.NEQ {
+			return
+		}
+		x, lit := cmp.X, cmp.Y
+		if _, ok := stringLiteral(pass, lit); !ok {
+			x, lit = cmp.Y, cmp.X
+		}
+		s, ok := stringLiteral(pass, lit)
+		if !ok {
+			return
+		}
+		value, isConversion := enumOperand(pass, x)
+		if value == nil {
+			return
+		}
+		tn, e := enums.Lookup(pass.TypesInfo.TypeOf(value))
+		if e == nil {
+			return
+		}
+		consts := e.ConstantsNamed(s)
+		if len(consts) == 0 {
+			return
+		}
+		constName := consts[0].Name
+		qualified, qualifiedOK := qualify(pass, file, tn.Pkg(), constName)
+		if !qualifiedOK {
+			qualified = tn.Pkg().Name() + "." + constName
+		}
+		if isConversion {
+			pass.Reportf(cmp.Pos(), "%s converts %s to a character, not to its name; did you mean to compare %s with %s?",
+				types.ExprString(x), types.ExprString(value), types.ExprString(value), qualified)
+			return
+		}
+		d := analysis.Diagnostic{
+			Pos:     cmp.Pos(),
+			End:     cmp.End(),
+			Message: fmt.Sprintf("comparison of %s with %s; compare %s with %s instead", types.ExprString(x), strconv.Quote(s), types.ExprString(value), qualified),
+		}
+		if qualifiedOK {
+			d.SuggestedFixes = []analysis.SuggestedFix{{
+				Message: "Compare with " + qualified,
+				TextEdits: []analysis.TextEdit{{
+					Pos:     cmp.Pos(),
+					End:     cmp.End(),
+					NewText: []byte(fmt.Sprintf("%s %s %s", types.ExprString(value), cmp.Op, qualified)),
+				}},
+			}}
+		}
+		pass.Report(d)
+	})
+	return nil, nil
+}
+
+// stringLiteral returns the value of a constant string expression.
+func stringLiteral(pass *analysis.Pass, expr ast.Expr) (string, bool) {
+	tv := pass.TypesInfo.Types[expr]
+	if tv.Value == nil || tv.Value.Kind() != constant.String {
+		return "", false
+	}
+	return constant.StringVal(tv.Value), true
+}
+
+// enumOperand returns x of x.String() or string(x), and whether it is the
+// latter conversion.
+func enumOperand(pass *analysis.Pass, expr ast.Expr) (ast.Expr, bool) {
+	call, ok := ast.Unparen(expr).(*ast.CallExpr)
+	if !ok {
+		return nil, false
+	}
+	switch fun := ast.Unparen(call.Fun).(type) {
+	case *ast.SelectorExpr:
+		// x.String()
+		sel := pass.TypesInfo.Selections[fun]
+		if len(call.Args) != 0 || sel == nil || sel.Kind() != types.MethodVal || fun.Sel.Name != "String" {
+			return nil, false
+		}
+		return ast.Unparen(fun.X), false
+	case *ast.Ident:
+		// string(x)
+		if len(call.Args) != 1 || pass.TypesInfo.Uses[fun] != types.Universe.Lookup("string") {
+			return nil, false
+		}
+		return ast.Unparen(call.Args[0]), true
+	}
+	return nil, false
+}
+
+// qualify returns the constant name as referred to from the file, if its
+// package is the analyzed one or is imported by the file.
+func qualify(pass *analysis.Pass, file *ast.File, pkg *types.Package, name string) (string, bool) {
+	if pkg == pass.Pkg {
+		return name, true
+	}
+	for _, spec := range file.Imports {
+		path, err := strconv.Unquote(spec.Path.Value)
+		if err != nil || path != pkg.Path() {
+			continue
+		}
+		switch {
+		case spec.Name == nil:
+			return pkg.Name() + "." + name, true
+		case spec.Name.Name != "_" && spec.Name.Name != ".":
+			return spec.Name.Name + "." + name, true
+		case spec.Name.Name == ".":
+			return name, true
+		}
+	}
+	return "", false
+}
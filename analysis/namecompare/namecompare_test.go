@@ -0,0 +1,14 @@
+package namecompare_test
+
+import (
+	"testing"
+
+	"golang.org/x/tools/go/analysis/analysistest"
+
+	"github.com/capsule8/enumer/analysis/namecompare"
+)
+
+func TestAnalyzer(t *testing.T) {
+	testdata := analysistest.TestData()
+	analysistest.RunWithSuggestedFixes(t, testdata, namecompare.Analyzer, "a")
+}
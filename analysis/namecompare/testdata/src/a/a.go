@@ -0,0 +1,20 @@
+package a
+
+import (
+	days "day"
+)
+
+type Shift struct{ Day days.Day }
+
+func f(d days.Day, s Shift) bool {
+	if d.String() == "monday" { // want `comparison of d.String\(\) with "monday"; compare d with days.Monday instead`
+		return true
+	}
+	if "tues_day" != (s.Day).String() { // want `comparison of \(s.Day\).String\(\) with "tues_day"; compare s.Day with days.TuesDay instead`
+		return true
+	}
+	if d.String() == "Monday" {
+		return true
+	}
+	return string(d) == "monday" // want `string\(d\) converts d to a character, not to its name; did you mean to compare d with days.Monday\?`
+}
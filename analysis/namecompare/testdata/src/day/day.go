@@ -0,0 +1,10 @@
+package day
+
+type Day int
+
+const (
+	Monday Day = iota
+	TuesDay
+	// Deprecated: use Monday.
+	FirstDay = Monday
+)
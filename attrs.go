@@ -0,0 +1,211 @@
+package main
+
+import (
+	"fmt"
+	"go/ast"
+	"go/constant"
+	"go/token"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// attrDirective starts the comments attaching attributes to a constant, e.g.
+//
+//	NotFound Status = 4 //enumer:attr status=404 severity=warn label="Not found"
+//
+// On the type declaration, it sets the default of attributes, which makes
+// them optional on the constants.
+const attrDirective = "//enumer:attr"
+
+// attr is an attribute literal, with the Go type it implies: int, float64,
+// bool or string.
+type attr struct {
+	kind string
+	lit  string // Go literal of the value.
+}
+
+// parseAttrs returns the attributes declared by the enumer:attr directives of
+// the comment groups.
+func parseAttrs(groups ...*ast.CommentGroup) (map[string]attr, error) {
+	var attrs map[string]attr
+	for _, group := range groups {
+		if group == nil {
+			continue
+		}
+		for _, c := range group.List {
+			if !strings.HasPrefix(c.Text, attrDirective+" ") {
+				continue
+			}
+			s := strings.TrimSpace(strings.TrimPrefix(c.Text, attrDirective))
+			for s != "" {
+				eq := strings.Index(s, "=")
+				if eq < 0 {
+					return nil, fmt.Errorf("malformed attribute %q: want key=value", s)
+				}
+				key := s[:eq]
+				if !token.IsIdentifier(key) {
+					return nil, fmt.Errorf("invalid attribute name %q", key)
+				}
+				s = s[eq+1:]
+				var lit string
+				if strings.HasPrefix(s, `"`) {
+					var err error
+					if lit, err = strconv.QuotedPrefix(s); err != nil {
+						return nil, fmt.Errorf("attribute %s: malformed string %s", key, s)
+					}
+				} else if end := strings.IndexFunc(s, unicode.IsSpace); end >= 0 {
+					lit = s[:end]
+				} else {
+					lit = s
+				}
+				s = s[len(lit):]
+				if s != "" && !unicode.IsSpace(rune(s[0])) {
+					return nil, fmt.Errorf("attribute %s: missing space after %s", key, lit)
+				}
+				s = strings.TrimSpace(s)
+				if attrs == nil {
+					attrs = make(map[string]attr)
+				}
+				if _, ok := attrs[key]; ok {
+					return nil, fmt.Errorf("attribute %s is set twice", key)
+				}
+				attrs[key] = newAttr(lit)
+			}
+		}
+	}
+	return attrs, nil
+}
+
+// newAttr infers the type of the literal. Numbers are the Go integer and
+// floating-point literals, signed or not; other unquoted words, e.g. inf, are
+// strings.
+func newAttr(lit string) attr {
+	switch {
+	case strings.HasPrefix(lit, `"`):
+		return attr{kind: "string", lit: lit}
+	case lit == "true" || lit == "false":
+		return attr{kind: "bool", lit: lit}
+	}
+	unsigned := strings.TrimPrefix(strings.TrimPrefix(lit, "-"), "+")
+	if v := constant.MakeFromLiteral(unsigned, token.INT, 0); v.Kind() == constant.Int {
+		if _, exact := constant.Int64Val(v); exact {
+			return attr{kind: "int", lit: lit}
+		}
+	}
+	if v := constant.MakeFromLiteral(unsigned, token.FLOAT, 0); v.Kind() == constant.Float || v.Kind() == constant.Int {
+		return attr{kind: "float64", lit: lit}
+	}
+	return attr{kind: "string", lit: strconv.Quote(lit)}
+}
+
+// attrMethodName returns the name of the accessor of the attribute, e.g.
+// HttpStatus for http_status.
+func attrMethodName(key string) string {
+	var b strings.Builder
+	for _, part := range strings.Split(key, "_") {
+		if part == "" {
+			continue
+		}
+		b.WriteString(strings.ToUpper(part[:1]))
+		b.WriteString(part[1:])
+	}
+	return b.String()
+}
+
+// generatedMethods holds the methods enumer may generate on a type, besides
+// IsA<Type>, which the accessors of the attributes must not redeclare.
+var generatedMethods = map[string]bool{
+	"String": true, "MarshalJSON": true, "UnmarshalJSON": true,
+	"MarshalText": true, "UnmarshalText": true, "MarshalYAML": true, "UnmarshalYAML": true,
+	"Value": true, "Scan": true, "ToProto": true, "MarshalGQL": true, "UnmarshalGQL": true,
+	"JSONSchema": true, "Description": true, "IsDeprecated": true, "EnumDescriptor": true,
+	"JSONName": true, "SQLName": true,
+}
+
+// typeAttrs returns the attribute defaults declared on the type.
+func (g *Generator) typeAttrs(typeName string) map[string]attr {
+	attrs, err := parseAttrs(g.typeDocGroup(typeName))
+	if err != nil {
+		fatalf("type %s: %s", typeName, err)
+	}
+	return attrs
+}
+
+// buildAttrMethods writes an accessor per attribute of the values, backed by
+// a map from value to attribute. Attributes without a default on the type
+// must be set on every value.
+func (g *Generator) buildAttrMethods(runs [][]Value, typeName string, defaults map[string]attr) {
+	kinds := make(map[string]string)
+	for key, a := range defaults {
+		kinds[key] = a.kind
+	}
+	for _, values := range runs {
+		for _, value := range values {
+			for key, a := range value.attrs {
+				kind, ok := kinds[key]
+				switch {
+				case !ok || kind == a.kind:
+					kinds[key] = a.kind
+				case kind == "int" && a.kind == "float64", kind == "float64" && a.kind == "int":
+					kinds[key] = "float64"
+				default:
+					fatalf("attribute %s of %s is a %s for %s but a %s elsewhere", key, typeName, a.kind, value.constName, kind)
+				}
+			}
+		}
+	}
+	if len(kinds) == 0 {
+		return
+	}
+	keys := make([]string, 0, len(kinds))
+	for key := range kinds {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	methods := make(map[string]string)
+	for _, key := range keys {
+		method := attrMethodName(key)
+		if generatedMethods[method] || method == "IsA"+typeName {
+			fatalf("attribute %s of %s: method %s is generated already", key, typeName, method)
+		}
+		if other, ok := methods[method]; ok {
+			fatalf("attributes %s and %s of %s both need a method %s", other, key, typeName, method)
+		}
+		methods[method] = key
+
+		var missing []string
+		g.Printf("\nvar _%s%sMap = map[%s]%s{\n", typeName, method, typeName, kinds[key])
+		for _, values := range runs {
+			for _, value := range values {
+				a, ok := value.attrs[key]
+				if !ok {
+					if a, ok = defaults[key]; !ok {
+						missing = append(missing, value.constName)
+						continue
+					}
+				}
+				g.Printf("\t%s: %s,\n", &value, a.lit)
+			}
+		}
+		g.Printf("}\n")
+		if len(missing) > 0 {
+			fatalf("attribute %s of %s is missing on %s", key, typeName, strings.Join(missing, ", "))
+		}
+		g.Printf(attrMethod, typeName, method, kinds[key], key)
+	}
+}
+
+// Arguments to format are:
+//	[1]: type name
+//	[2]: method name
+//	[3]: attribute type
+//	[4]: attribute name
+const attrMethod = `
+// %[2]s returns the %[4]s attribute of i, or the zero value if i is not a %[1]s value.
+func (i %[1]s) %[2]s() %[3]s {
+	return _%[1]s%[2]sMap[i]
+}
+`
@@ -0,0 +1,86 @@
+// This file contains tests for the enumer:attr comments.
+
+package main
+
+import (
+	"go/ast"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func commentGroup(lines ...string) *ast.CommentGroup {
+	group := &ast.CommentGroup{}
+	for _, line := range lines {
+		group.List = append(group.List, &ast.Comment{Text: line})
+	}
+	return group
+}
+
+func TestParseAttrs(t *testing.T) {
+	attrs, err := parseAttrs(
+		commentGroup("// NotFound is returned for missing pages.", `//enumer:attr status=0x194 label="Not found"`),
+		nil,
+		commentGroup("//enumer:attr severity=warn retry=false weight=-2.5"),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := map[string]attr{
+		"status":   {"int", "0x194"},
+		"label":    {"string", `"Not found"`},
+		"severity": {"string", `"warn"`},
+		"retry":    {"bool", "false"},
+		"weight":   {"float64", "-2.5"},
+	}
+	if !reflect.DeepEqual(attrs, expected) {
+		t.Errorf("got %v; expected %v", attrs, expected)
+	}
+}
+
+func TestBadAttrs(t *testing.T) {
+	for line, expected := range map[string]string{
+		"//enumer:attr status":             "want key=value",
+		"//enumer:attr 1x=2":               "invalid attribute name",
+		`//enumer:attr label="Not found`:   "malformed string",
+		"//enumer:attr status=1 status=2":  "set twice",
+		"//enumer:attr a-b=1":              "invalid attribute name",
+		`//enumer:attr label="x"status=2"`: "missing space",
+	} {
+		_, err := parseAttrs(commentGroup(line))
+		if err == nil || !strings.Contains(err.Error(), expected) {
+			t.Errorf("%s: got error %v; expected %q", line, err, expected)
+		}
+	}
+}
+
+func TestNewAttr(t *testing.T) {
+	for lit, expected := range map[string]attr{
+		"404":      {"int", "404"},
+		"-0x1F":    {"int", "-0x1F"},
+		"1_000":    {"int", "1_000"},
+		"+2.5e3":   {"float64", "+2.5e3"},
+		".5":       {"float64", ".5"},
+		"inf":      {"string", `"inf"`},
+		"+Inf":     {"string", `"+Inf"`},
+		"NaN":      {"string", `"NaN"`},
+		"infinity": {"string", `"infinity"`},
+		"1x":       {"string", `"1x"`},
+	} {
+		if got := newAttr(lit); got != expected {
+			t.Errorf("%s: got %v; expected %v", lit, got, expected)
+		}
+	}
+}
+
+func TestAttrMethodName(t *testing.T) {
+	for key, expected := range map[string]string{
+		"status":      "Status",
+		"http_status": "HttpStatus",
+		"displayName": "DisplayName",
+	} {
+		if got := attrMethodName(key); got != expected {
+			t.Errorf("%s: got %s; expected %s", key, got, expected)
+		}
+	}
+}
@@ -0,0 +1,156 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"math"
+	"path/filepath"
+	"strings"
+	"unicode"
+)
+
+// cIndexLimit is the largest value that still gets a names table indexed by
+// value. Sparser enums get parallel values and names tables instead.
+const cIndexLimit = 1 << 12
+
+// cGuard returns the include guard macro for the named header file.
+func cGuard(fileName string) string {
+	return strings.Map(func(r rune) rune {
+		if r > unicode.MaxASCII || !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+			return '_'
+		}
+		return unicode.ToUpper(r)
+	}, filepath.Base(fileName))
+}
+
+// cString returns s as a C string literal.
+func cString(s string) string {
+	var b strings.Builder
+	b.WriteByte('"')
+	for i := 0; i < len(s); i++ {
+		switch c := s[i]; {
+		case c == '"' || c == '\\':
+			b.WriteByte('\\')
+			b.WriteByte(c)
+		case c < ' ' || c >= 0x7f:
+			// Octal escapes, unlike hex ones, never swallow the next character.
+			fmt.Fprintf(&b, "\\%03o", c)
+		default:
+			b.WriteByte(c)
+		}
+	}
+	b.WriteByte('"')
+	return b.String()
+}
+
+// cValue returns the value as a C integer constant. Unsigned values too large
+// for a long long get a ULL suffix, and negative values are parenthesized so
+// that macros expand to a single operand.
+func cValue(v Value) string {
+	switch {
+	case v.signed && int64(v.value) < 0:
+		return "(" + v.str + ")"
+	case !v.signed && v.value > math.MaxInt64:
+		return v.str + "ULL"
+	}
+	return v.str
+}
+
+// checkCNames fails if values of the type share a C name, or if a value is
+// named like the count macro, e.g. DAY_COUNT for the constant Count of type
+// Day.
+func checkCNames(runs [][]Value, typeName string, trimPrefix string, acronyms []string) error {
+	count := protoEnumPrefix(typeName, acronyms) + "COUNT"
+	owners := make(map[string]Value)
+	for _, values := range runs {
+		for _, value := range values {
+			n := protoValueName(typeName, value, trimPrefix, acronyms)
+			if n == count {
+				return fmt.Errorf("value %s of %s is named %s in C, the name of the count macro", value.constName, typeName, count)
+			}
+			if other, ok := owners[n]; ok {
+				return fmt.Errorf("values %s and %s of %s are both named %s in C", other.constName, value.constName, typeName, n)
+			}
+			owners[n] = value
+		}
+	}
+	return nil
+}
+
+// buildCHeader writes the C definitions for the type: the constants, as an
+// enum or as #defines, and a table of their names. Constants are named like
+// proto values, e.g. DAY_MONDAY for the constant Monday of type Day.
+func (g *Generator) buildCHeader(runs [][]Value, typeName string, trimPrefix string, defines bool) {
+	if err := checkCNames(runs, typeName, trimPrefix, g.acronyms); err != nil {
+		fatalf("%s", err)
+	}
+	b := &g.cBuf
+	lower := strings.ToLower(delimit(typeName, '_', g.acronyms))
+	fmt.Fprintf(b, "\n")
+	if defines {
+		for _, values := range runs {
+			for _, value := range values {
+				fmt.Fprintf(b, "#define %s %s\n", protoValueName(typeName, value, trimPrefix, g.acronyms), cValue(value))
+			}
+		}
+	} else {
+		fmt.Fprintf(b, "enum %s {\n", lower)
+		for _, values := range runs {
+			for _, value := range values {
+				fmt.Fprintf(b, "\t%s = %s,\n", protoValueName(typeName, value, trimPrefix, g.acronyms), cValue(value))
+			}
+		}
+		fmt.Fprintf(b, "};\n")
+	}
+
+	first, last := runs[0][0], runs[len(runs)-1][len(runs[len(runs)-1])-1]
+	if (!first.signed || int64(first.value) >= 0) && last.value < cIndexLimit {
+		// Names indexed by value, e.g. day_names[DAY_MONDAY].
+		fmt.Fprintf(b, "\nstatic const char *%s_names[] = {\n", lower)
+		for _, values := range runs {
+			for _, value := range values {
+				fmt.Fprintf(b, "\t[%s] = %s,\n", protoValueName(typeName, value, trimPrefix, g.acronyms), cString(value.name))
+			}
+		}
+		fmt.Fprintf(b, "};\n")
+	} else {
+		// Names paired by position with the values, e.g. day_names[i] is
+		// the name of day_values[i].
+		ctype := "long long"
+		if !first.signed {
+			ctype = "unsigned long long"
+		}
+		fmt.Fprintf(b, "\nstatic const %s %s_values[] = {\n", ctype, lower)
+		for _, values := range runs {
+			for _, value := range values {
+				fmt.Fprintf(b, "\t%s,\n", protoValueName(typeName, value, trimPrefix, g.acronyms))
+			}
+		}
+		fmt.Fprintf(b, "};\n")
+		fmt.Fprintf(b, "\nstatic const char *%s_names[] = {\n", lower)
+		for _, values := range runs {
+			for _, value := range values {
+				fmt.Fprintf(b, "\t%s,\n", cString(value.name))
+			}
+		}
+		fmt.Fprintf(b, "};\n")
+	}
+
+	n := 0
+	for _, values := range runs {
+		n += len(values)
+	}
+	fmt.Fprintf(b, "\n#define %sCOUNT %d\n", protoEnumPrefix(typeName, g.acronyms), n)
+}
+
+// cHeader returns the complete header file holding the accumulated
+// definitions.
+func (g *Generator) cHeader(fileName, header string) []byte {
+	var b bytes.Buffer
+	guard := cGuard(fileName)
+	fmt.Fprintf(&b, "%s\n", header)
+	fmt.Fprintf(&b, "#ifndef %s\n#define %s\n", guard, guard)
+	b.Write(g.cBuf.Bytes())
+	fmt.Fprintf(&b, "\n#endif /* %s */\n", guard)
+	return b.Bytes()
+}
@@ -0,0 +1,47 @@
+// This file contains tests for the C header output.
+
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestCValue(t *testing.T) {
+	for _, test := range []struct {
+		value    Value
+		expected string
+	}{
+		{Value{value: 2, str: "2", signed: true}, "2"},
+		{Value{value: ^uint64(1), str: "-2", signed: true}, "(-2)"},
+		{Value{value: 1 << 63, str: "9223372036854775808"}, "9223372036854775808ULL"},
+		{Value{value: 1<<63 - 1, str: "9223372036854775807"}, "9223372036854775807"},
+	} {
+		if got := cValue(test.value); got != test.expected {
+			t.Errorf("cValue(%s) = %s; expected %s", test.value.str, got, test.expected)
+		}
+	}
+}
+
+func TestCheckCNames(t *testing.T) {
+	day := func(constName string, value int64) Value {
+		return Value{constName: constName, value: uint64(value), signed: true}
+	}
+	tests := []struct {
+		values   []Value
+		expected string // A substring of the error, or "" if none.
+	}{
+		{[]Value{day("Monday", 0), day("Tuesday", 1), day("Yesterday", -1)}, ""},
+		{[]Value{day("Monday", 1), day("DayMonday", 2)}, "values Monday and DayMonday of Day are both named DAY_MONDAY in C"},
+		{[]Value{day("Monday", 1), day("DayCount", 2)}, "value DayCount of Day is named DAY_COUNT in C"},
+	}
+	for _, test := range tests {
+		err := checkCNames([][]Value{test.values}, "Day", "", nil)
+		switch {
+		case test.expected == "" && err != nil:
+			t.Errorf("%v: got error %v", test.values, err)
+		case test.expected != "" && (err == nil || !strings.Contains(err.Error(), test.expected)):
+			t.Errorf("%v: got error %v; expected %q", test.values, err, test.expected)
+		}
+	}
+}
@@ -0,0 +1,415 @@
+package main
+
+import (
+	"fmt"
+	"io/ioutil"
+	"log"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// cConst is an integer constant declared in a C header, either as an
+// enumerator or as an object-like macro.
+type cConst struct {
+	name  string
+	value int64
+	err   error // Why the value of an enumerator could not be evaluated.
+}
+
+var (
+	cBlockComment = regexp.MustCompile(`(?s)/\*.*?\*/`)
+	cLineComment  = regexp.MustCompile(`//[^\n]*`)
+	cDefine       = regexp.MustCompile(`^\s*#\s*define\s+([A-Za-z_]\w*)\s+(.+)$`)
+	cEnum         = regexp.MustCompile(`(?s)\benum\b\s*[A-Za-z_]?\w*\s*\{(.*?)\}`)
+)
+
+// parseCHeader returns the integer constants declared in the header source,
+// in order of declaration. Macros whose body is not an integer constant
+// expression are ignored. Enumerators whose value cannot be evaluated are
+// returned with an error, as are the ones following them in their enum.
+func parseCHeader(src string) []cConst {
+	src = strings.ReplaceAll(src, "\\\r\n", " ")
+	src = strings.ReplaceAll(src, "\\\n", " ")
+	src = cBlockComment.ReplaceAllString(src, " ")
+	src = cLineComment.ReplaceAllString(src, "")
+
+	known := make(map[string]int64)
+	var consts []cConst
+	declareEnum := func(body string) {
+		var (
+			next int64
+			err  error
+		)
+		for _, enumerator := range strings.Split(body, ",") {
+			enumerator = strings.TrimSpace(enumerator)
+			if enumerator == "" {
+				continue
+			}
+			name := enumerator
+			if i := strings.Index(enumerator, "="); i >= 0 {
+				name = strings.TrimSpace(enumerator[:i])
+				if err == nil {
+					next, err = evalCExpr(enumerator[i+1:], known)
+				}
+			}
+			if err != nil {
+				consts = append(consts, cConst{name: name, err: err})
+				continue
+			}
+			known[name] = next
+			consts = append(consts, cConst{name: name, value: next})
+			next++
+		}
+	}
+
+	// Enums and macros may refer to each other, so both are handled in
+	// source order: an enum when the line it starts on is reached.
+	enums := cEnum.FindAllStringSubmatchIndex(src, -1)
+	pos := 0
+	for _, line := range strings.SplitAfter(src, "\n") {
+		end := pos + len(line)
+		for len(enums) > 0 && enums[0][0] < end {
+			declareEnum(src[enums[0][2]:enums[0][3]])
+			enums = enums[1:]
+		}
+		pos = end
+		if m := cDefine.FindStringSubmatch(strings.TrimRight(line, "\n")); m != nil {
+			if v, err := evalCExpr(m[2], known); err == nil {
+				known[m[1]] = v
+				consts = append(consts, cConst{name: m[1], value: v})
+			}
+		}
+	}
+	return consts
+}
+
+// cToken is a token of a C constant expression: an operator, an identifier or
+// an integer.
+type cToken struct {
+	op    string
+	ident string
+	value int64
+}
+
+// tokenizeCExpr splits a C constant expression into tokens.
+func tokenizeCExpr(s string) ([]cToken, error) {
+	var toks []cToken
+	for i := 0; i < len(s); {
+		c := s[i]
+		switch {
+		case c == ' ' || c == '\t' || c == '\r' || c == '\n':
+			i++
+		case c >= '0' && c <= '9':
+			j := i
+			for j < len(s) && isCIdentByte(s[j]) {
+				j++
+			}
+			lit := strings.TrimRight(strings.ToLower(s[i:j]), "ul")
+			var (
+				v   uint64
+				err error
+			)
+			switch {
+			case strings.HasPrefix(lit, "0x"):
+				v, err = strconv.ParseUint(lit[2:], 16, 64)
+			case strings.HasPrefix(lit, "0b"):
+				v, err = strconv.ParseUint(lit[2:], 2, 64)
+			case len(lit) > 1 && lit[0] == '0':
+				v, err = strconv.ParseUint(lit[1:], 8, 64)
+			default:
+				v, err = strconv.ParseUint(lit, 10, 64)
+			}
+			if err != nil {
+				return nil, fmt.Errorf("invalid integer %q", s[i:j])
+			}
+			toks = append(toks, cToken{value: int64(v)})
+			i = j
+		case isCIdentByte(c):
+			j := i
+			for j < len(s) && isCIdentByte(s[j]) {
+				j++
+			}
+			toks = append(toks, cToken{ident: s[i:j]})
+			i = j
+		default:
+			op := string(c)
+			if i+1 < len(s) {
+				switch two := s[i : i+2]; two {
+				case "<<", ">>":
+					op = two
+				}
+			}
+			switch op {
+			case "+", "-", "*", "/", "%", "<<", ">>", "&", "|", "^", "~", "!", "(", ")":
+			default:
+				return nil, fmt.Errorf("unexpected %q", op)
+			}
+			toks = append(toks, cToken{op: op})
+			i += len(op)
+		}
+	}
+	return toks, nil
+}
+
+func isCIdentByte(c byte) bool {
+	return c == '_' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9'
+}
+
+// cBinaryPrecedence holds the precedence of the supported binary operators.
+var cBinaryPrecedence = map[string]int{
+	"|":  1,
+	"^":  2,
+	"&":  3,
+	"<<": 4, ">>": 4,
+	"+": 5, "-": 5,
+	"*": 6, "/": 6, "%": 6,
+}
+
+// cExprParser evaluates a C integer constant expression made of literals,
+// known constants, casts, parentheses and the arithmetic and bitwise
+// operators.
+type cExprParser struct {
+	toks  []cToken
+	known map[string]int64
+}
+
+// evalCExpr evaluates the C constant expression s, resolving identifiers
+// through known.
+func evalCExpr(s string, known map[string]int64) (int64, error) {
+	toks, err := tokenizeCExpr(s)
+	if err != nil {
+		return 0, err
+	}
+	if len(toks) == 0 {
+		return 0, fmt.Errorf("empty expression")
+	}
+	p := &cExprParser{toks: toks, known: known}
+	v, err := p.binary(1)
+	if err != nil {
+		return 0, err
+	}
+	if len(p.toks) > 0 {
+		return 0, fmt.Errorf("unexpected trailing tokens in %q", s)
+	}
+	return v, nil
+}
+
+func (p *cExprParser) binary(prec int) (int64, error) {
+	x, err := p.unary()
+	if err != nil {
+		return 0, err
+	}
+	for len(p.toks) > 0 {
+		op := p.toks[0].op
+		opPrec, ok := cBinaryPrecedence[op]
+		if !ok || opPrec < prec {
+			break
+		}
+		p.toks = p.toks[1:]
+		y, err := p.binary(opPrec + 1)
+		if err != nil {
+			return 0, err
+		}
+		switch op {
+		case "|":
+			x |= y
+		case "^":
+			x ^= y
+		case "&":
+			x &= y
+		case "<<":
+			x <<= uint64(y)
+		case ">>":
+			x >>= uint64(y)
+		case "+":
+			x += y
+		case "-":
+			x -= y
+		case "*":
+			x *= y
+		case "/", "%":
+			if y == 0 {
+				return 0, fmt.Errorf("division by zero")
+			}
+			if op == "/" {
+				x /= y
+			} else {
+				x %= y
+			}
+		}
+	}
+	return x, nil
+}
+
+func (p *cExprParser) unary() (int64, error) {
+	if len(p.toks) == 0 {
+		return 0, fmt.Errorf("unexpected end of expression")
+	}
+	tok := p.toks[0]
+	p.toks = p.toks[1:]
+	switch {
+	case tok.op == "-", tok.op == "+", tok.op == "~", tok.op == "!":
+		x, err := p.unary()
+		if err != nil {
+			return 0, err
+		}
+		switch tok.op {
+		case "-":
+			return -x, nil
+		case "~":
+			return ^x, nil
+		case "!":
+			if x == 0 {
+				return 1, nil
+			}
+			return 0, nil
+		}
+		return x, nil
+	case tok.op == "(":
+		if p.isCast() {
+			for p.toks[0].op != ")" {
+				p.toks = p.toks[1:]
+			}
+			p.toks = p.toks[1:]
+			return p.unary()
+		}
+		x, err := p.binary(1)
+		if err != nil {
+			return 0, err
+		}
+		if len(p.toks) == 0 || p.toks[0].op != ")" {
+			return 0, fmt.Errorf("missing )")
+		}
+		p.toks = p.toks[1:]
+		return x, nil
+	case tok.ident != "":
+		v, ok := p.known[tok.ident]
+		if !ok {
+			return 0, fmt.Errorf("unknown identifier %s", tok.ident)
+		}
+		return v, nil
+	case tok.op == "":
+		return tok.value, nil
+	}
+	return 0, fmt.Errorf("unexpected %q", tok.op)
+}
+
+// isCast reports whether the tokens following an opening parenthesis are a
+// type name such as (unsigned long) or (__u32).
+func (p *cExprParser) isCast() bool {
+	for i, tok := range p.toks {
+		switch {
+		case tok.op == ")":
+			return i > 0
+		case tok.ident == "":
+			return false
+		default:
+			if _, ok := p.known[tok.ident]; ok {
+				return false
+			}
+		}
+	}
+	return false
+}
+
+// goConstName turns a C constant name, already stripped of its prefix, into
+// a Go identifier, e.g. OPEN_FILE into OpenFile.
+func goConstName(cName, typeName string) string {
+	var b strings.Builder
+	for _, part := range strings.Split(cName, "_") {
+		if part == "" {
+			continue
+		}
+		b.WriteString(strings.ToUpper(part[:1]))
+		b.WriteString(strings.ToLower(part[1:]))
+	}
+	s := b.String()
+	if s == "" || !unicode.IsLetter(rune(s[0])) {
+		// Not a valid identifier on its own, e.g. 1K.
+		s = typeName + s
+	}
+	return s
+}
+
+// cValues returns the values of the type for the constants whose names start
+// with prefix. It fails if the value of one of them is unknown, or if two of
+// them get the same Go name.
+func cValues(consts []cConst, typeName, prefix string) ([]Value, error) {
+	var values []Value
+	owners := make(map[string]string) // Go name => C name.
+	for _, c := range consts {
+		if !strings.HasPrefix(c.name, prefix) || c.name == prefix {
+			continue
+		}
+		if c.err != nil {
+			return nil, fmt.Errorf("%s: %s", c.name, c.err)
+		}
+		constName := goConstName(strings.TrimPrefix(c.name, prefix), typeName)
+		if other, ok := owners[constName]; ok {
+			return nil, fmt.Errorf("%s and %s are both imported as %s", other, c.name, constName)
+		}
+		owners[constName] = c.name
+		values = append(values, Value{
+			constName: constName,
+			name:      constName,
+			value:     uint64(c.value),
+			signed:    true,
+			str:       strconv.FormatInt(c.value, 10),
+			comment:   c.name,
+		})
+	}
+	return values, nil
+}
+
+// cUnderlying returns the underlying type of the values, all signed as
+// cValues returns them: int32 like a C enum, or uint32 or int64 if the values
+// do not fit.
+func cUnderlying(values []Value) string {
+	min, max := int64(values[0].value), int64(values[0].value)
+	for _, v := range values {
+		if n := int64(v.value); n < min {
+			min = n
+		} else if n > max {
+			max = n
+		}
+	}
+	switch {
+	case min >= math.MinInt32 && max <= math.MaxInt32:
+		return "int32"
+	case min >= 0 && max <= math.MaxUint32:
+		return "uint32"
+	}
+	return "int64"
+}
+
+// importCHeader defines the type from the integer constants of the named C
+// header whose names start with prefix.
+func (g *Generator) importCHeader(fileName, typeName, prefix string) {
+	src, err := ioutil.ReadFile(fileName)
+	if err != nil {
+		log.Fatal(err)
+	}
+	consts := parseCHeader(string(src))
+	def := &definedType{
+		doc: fmt.Sprintf("%s mirrors the %s constants of %s.", typeName, prefix+"*", fileName),
+	}
+	if prefix == "" {
+		def.doc = fmt.Sprintf("%s mirrors the constants of %s.", typeName, fileName)
+	}
+	if def.values, err = cValues(consts, typeName, prefix); err != nil {
+		log.Fatalf("%s: %s", fileName, err)
+	}
+	if len(def.values) == 0 {
+		log.Fatalf("%s: no integer constants with prefix %q", fileName, prefix)
+	}
+	def.underlying = cUnderlying(def.values)
+	if def.underlying == "uint32" {
+		for i := range def.values {
+			def.values[i].signed = false
+		}
+	}
+	g.define(typeName, def)
+}
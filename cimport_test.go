@@ -0,0 +1,146 @@
+// This file contains tests for the C header import.
+
+package main
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+const eventHeader = `#ifndef EVENT_H
+#define EVENT_H
+
+#define EVENT_BASE 0x10
+#define EVENT_OPEN (EVENT_BASE + 1) /* open(2) */
+#define EVENT_CLOSE ((unsigned int)EVENT_BASE << 1)
+#define EVENT_NAME "event"
+#define EVENT_MASK (EVENT_OPEN | \
+                    EVENT_CLOSE)
+
+enum event_kind {
+	KIND_A,
+	KIND_B = EVENT_CLOSE + 2, // After close.
+	KIND_C,
+	KIND_D = sizeof(int),
+	KIND_E,
+};
+
+#endif
+`
+
+func TestParseCHeader(t *testing.T) {
+	consts := parseCHeader(eventHeader)
+	var got []cConst
+	for _, c := range consts {
+		if c.err != nil {
+			got = append(got, cConst{name: c.name, value: -1})
+			continue
+		}
+		got = append(got, c)
+	}
+	expected := []cConst{
+		{name: "EVENT_BASE", value: 0x10},
+		{name: "EVENT_OPEN", value: 0x11},
+		{name: "EVENT_CLOSE", value: 0x20},
+		{name: "EVENT_MASK", value: 0x31},
+		{name: "KIND_A", value: 0},
+		{name: "KIND_B", value: 0x22},
+		{name: "KIND_C", value: 0x23},
+		{name: "KIND_D", value: -1},
+		{name: "KIND_E", value: -1},
+	}
+	if !reflect.DeepEqual(got, expected) {
+		t.Errorf("got %v; expected %v", got, expected)
+	}
+}
+
+var cExprTests = []struct {
+	expr  string
+	value int64
+	ok    bool
+}{
+	{"42", 42, true},
+	{"0x2aUL", 42, true},
+	{"052", 42, true},
+	{"0b101010", 42, true},
+	{"-1", -1, true},
+	{"~0 & 0xff", 0xff, true},
+	{"1 + 2 * 3", 7, true},
+	{"(1 + 2) * 3", 9, true},
+	{"1 << 4 | 1", 17, true},
+	{"X - 1", 9, true},
+	{"(unsigned long)X", 10, true},
+	{"(X)", 10, true},
+	{"!X", 0, true},
+	{"Y", 0, false},
+	{"1 / 0", 0, false},
+	{"1 +", 0, false},
+	{"\"str\"", 0, false},
+	{"(1", 0, false},
+}
+
+func TestEvalCExpr(t *testing.T) {
+	known := map[string]int64{"X": 10}
+	for _, test := range cExprTests {
+		v, err := evalCExpr(test.expr, known)
+		if test.ok != (err == nil) {
+			t.Errorf("%q: got error %v", test.expr, err)
+			continue
+		}
+		if v != test.value {
+			t.Errorf("%q: got %d; expected %d", test.expr, v, test.value)
+		}
+	}
+}
+
+func TestGoConstName(t *testing.T) {
+	for cName, expected := range map[string]string{
+		"OPEN":       "Open",
+		"OPEN_FILE":  "OpenFile",
+		"IPV4__ADDR": "Ipv4Addr",
+		"1K":         "Size1k",
+	} {
+		if got := goConstName(cName, "Size"); got != expected {
+			t.Errorf("%s: got %s; expected %s", cName, got, expected)
+		}
+	}
+}
+
+func TestCValuesCollision(t *testing.T) {
+	consts := []cConst{{name: "SIZE_A_B", value: 1}, {name: "SIZE_A__B", value: 2}}
+	expected := "SIZE_A_B and SIZE_A__B are both imported as AB"
+	if _, err := cValues(consts, "Size", "SIZE_"); err == nil || err.Error() != expected {
+		t.Errorf("got error %v; expected %s", err, expected)
+	}
+	if _, err := cValues(consts[:1], "Size", "SIZE_"); err != nil {
+		t.Errorf("got error %v without collisions", err)
+	}
+}
+
+func TestCUnderlying(t *testing.T) {
+	for _, test := range []struct {
+		values   []int64
+		expected string
+	}{
+		{[]int64{0, 1, 2}, "int32"},
+		{[]int64{-1 << 31, 1<<31 - 1}, "int32"},
+		{[]int64{0, 1 << 31}, "uint32"},
+		{[]int64{1<<32 - 1}, "uint32"},
+		{[]int64{-1, 1 << 31}, "int64"},
+		{[]int64{1 << 32}, "int64"},
+		{[]int64{-1<<31 - 1}, "int64"},
+	} {
+		var consts []cConst
+		for i, v := range test.values {
+			consts = append(consts, cConst{name: fmt.Sprintf("SIZE_%c", 'A'+i), value: v})
+		}
+		values, err := cValues(consts, "Size", "SIZE_")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := cUnderlying(values); got != test.expected {
+			t.Errorf("%v: got %s; expected %s", test.values, got, test.expected)
+		}
+	}
+}
@@ -0,0 +1,12 @@
+// The enumer-checkedconv command reports conversions of integers into enumer
+// types that are not checked. It can run on its own, or with
+// "go vet -vettool=$(which enumer-checkedconv)".
+package main
+
+import (
+	"golang.org/x/tools/go/analysis/singlechecker"
+
+	"github.com/capsule8/enumer/analysis/checkedconv"
+)
+
+func main() { singlechecker.Main(checkedconv.Analyzer) }
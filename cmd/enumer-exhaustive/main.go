@@ -0,0 +1,12 @@
+// The enumer-exhaustive command reports switch statements over enumer types
+// that miss some of their values. It can run on its own, or with
+// "go vet -vettool=$(which enumer-exhaustive)".
+package main
+
+import (
+	"golang.org/x/tools/go/analysis/singlechecker"
+
+	"github.com/capsule8/enumer/analysis/exhaustive"
+)
+
+func main() { singlechecker.Main(exhaustive.Analyzer) }
@@ -0,0 +1,12 @@
+// The enumer-namecompare command reports comparisons of the names of enumer
+// values with string literals. It can run on its own, or with
+// "go vet -vettool=$(which enumer-namecompare)".
+package main
+
+import (
+	"golang.org/x/tools/go/analysis/singlechecker"
+
+	"github.com/capsule8/enumer/analysis/namecompare"
+)
+
+func main() { singlechecker.Main(namecompare.Analyzer) }
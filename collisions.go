@@ -0,0 +1,100 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+)
+
+// nameCollisions lists the constants of different values with the same
+// name, which would be duplicate keys of a name to value map.
+type nameCollisions []string
+
+func (c nameCollisions) Error() string {
+	return "\n\t" + strings.Join(c, "\n\t")
+}
+
+// checkNameCollisions checks the final names of the values, after the trim,
+// the transform and the line comments, as the decoding with the case match
+// looks them up. It reports every name of several values with the
+// declarations of all their constants. Aliases of a value share its name.
+func checkNameCollisions(values []Value, match CaseMatch) error {
+	var keys []string
+	owners := make(map[string][]Value) // By folded name.
+	for _, v := range values {
+		key := match.fold(v.name)
+		if _, ok := owners[key]; !ok {
+			keys = append(keys, key)
+		}
+		shared := false
+		for _, other := range owners[key] {
+			shared = shared || other.value == v.value
+		}
+		if !shared {
+			owners[key] = append(owners[key], v)
+		}
+	}
+	var collisions nameCollisions
+	for _, key := range keys {
+		vs := owners[key]
+		if len(vs) < 2 {
+			continue
+		}
+		decls := make([]string, len(vs))
+		named := fmt.Sprintf("%q", vs[0].name)
+		for i, v := range vs {
+			decls[i] = declaration(v)
+			if v.name != vs[0].name {
+				named = fmt.Sprintf("%q ignoring case", key)
+			}
+		}
+		if len(vs) == 2 {
+			collisions = append(collisions, fmt.Sprintf("%s and %s are both named %s", decls[0], decls[1], named))
+		} else {
+			last := len(decls) - 1
+			collisions = append(collisions, fmt.Sprintf("%s and %s are all named %s", strings.Join(decls[:last], ", "), decls[last], named))
+		}
+	}
+	if len(collisions) > 0 {
+		return collisions
+	}
+	return nil
+}
+
+// fold returns the name as the decoding with the case match looks it up.
+func (m CaseMatch) fold(name string) string {
+	switch m {
+	case CaseLower, CaseMixed:
+		return strings.ToLower(name)
+	case CaseUpper:
+		return strings.ToUpper(name)
+	}
+	return name
+}
+
+// declaration returns the constant of the value with its position, if known.
+func declaration(v Value) string {
+	if v.pos.IsValid() {
+		return fmt.Sprintf("%s (%s)", v.constName, v.pos)
+	}
+	return v.constName
+}
+
+// anyHasPrefix reports whether the name of a value starts with prefix.
+func anyHasPrefix(values []Value, prefix string) bool {
+	for _, v := range values {
+		if strings.HasPrefix(v.name, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// anyEmpty reports whether a value is named with the empty string.
+func anyEmpty(values []Value) bool {
+	for _, v := range values {
+		if v.name == "" {
+			return true
+		}
+	}
+	return false
+}
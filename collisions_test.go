@@ -0,0 +1,132 @@
+// This file contains tests for the detection of name collisions and of the
+// flags that match nothing.
+
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"go/token"
+	"log"
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestCheckNameCollisions(t *testing.T) {
+	at := func(line int) token.Position { return token.Position{Filename: "day.go", Line: line, Column: 2} }
+	values := []Value{
+		{constName: "FooBar", name: "foo_bar", value: 0, pos: at(4)},
+		{constName: "Foo_Bar", name: "foo_bar", value: 1, pos: at(5)},
+		{constName: "FooBarAlias", name: "foo_bar", value: 0, pos: at(6)},
+		{constName: "Baz", name: "", value: 2, pos: at(7)},
+		{constName: "Qux", name: "", value: 3},
+		{constName: "FOO_BAR", name: "foo_bar", value: 4, pos: at(9)},
+	}
+	expected := `
+	FooBar (day.go:4:2), Foo_Bar (day.go:5:2) and FOO_BAR (day.go:9:2) are all named "foo_bar"
+	Baz (day.go:7:2) and Qux are both named ""`
+	if err := checkNameCollisions(values, CaseNone); err == nil || err.Error() != expected {
+		t.Errorf("got error %v; expected %s", err, expected)
+	}
+	if err := checkNameCollisions(values[:1], CaseNone); err != nil {
+		t.Errorf("got error %v without collisions", err)
+	}
+}
+
+func TestCheckNameCollisionsIgnoringCase(t *testing.T) {
+	values := []Value{
+		{constName: "Ok", name: "ok", value: 0},
+		{constName: "OK", name: "OK", value: 1},
+	}
+	if err := checkNameCollisions(values, CaseNone); err != nil {
+		t.Errorf("got error %v when matching the case", err)
+	}
+	expected := `
+	Ok and OK are both named "ok" ignoring case`
+	for _, match := range []CaseMatch{CaseLower, CaseMixed} {
+		if err := checkNameCollisions(values, match); err == nil || err.Error() != expected {
+			t.Errorf("%d: got error %v; expected %s", match, err, expected)
+		}
+	}
+	expected = `
+	Ok and OK are both named "OK" ignoring case`
+	if err := checkNameCollisions(values, CaseUpper); err == nil || err.Error() != expected {
+		t.Errorf("got error %v; expected %s", err, expected)
+	}
+}
+
+// TestUnusedFlagWarnings checks the warnings on -trimprefix and -empty
+// matching no constant.
+func TestUnusedFlagWarnings(t *testing.T) {
+	var buf bytes.Buffer
+	log.SetOutput(&buf)
+	defer log.SetOutput(os.Stderr)
+	tests := []struct {
+		transform  string
+		trimPrefix string
+		empty      string
+		warning    string // A substring of the warning, or "" if none.
+	}{
+		{"noop", "Mon", "", ""},
+		{"noop", "Week", "", `-trimprefix "Week" matches no constant of Day`},
+		{"noop", "", "Friday", ""},
+		{"lower", "", "friday", ""},
+		{"noop", "", "Someday", `-empty "Someday" matches no name of Day`},
+		{"lower", "", "Friday", `-empty "Friday" matches no name of Day`},
+	}
+	for _, test := range tests {
+		buf.Reset()
+		g := Generator{}
+		typeName := loadGolden(t, &g, Golden{"day", dayIn, ""})
+		g.generate(typeName, false, false, false, false, test.transform, test.trimPrefix, false, false, false, test.empty)
+		got := buf.String()
+		switch {
+		case test.warning == "" && got != "":
+			t.Errorf("%+v: unexpected warning %s", test, got)
+		case test.warning != "" && !strings.Contains(got, test.warning):
+			t.Errorf("%+v: got warning %q; expected %q", test, got, test.warning)
+		}
+	}
+}
+
+// TestDecodeAnyCollisions checks that, under -decodeany, the names of String
+// and of the sets are checked against each other.
+func TestDecodeAnyCollisions(t *testing.T) {
+	defer func(f func(string, ...interface{})) { fatalf = f }(fatalf)
+	var got string
+	fatalf = func(format string, args ...interface{}) {
+		if got == "" {
+			got = fmt.Sprintf(format, args...)
+		}
+	}
+	const input = `type Grade int
+
+const (
+	Top  Grade = iota //enumer:attr json=%s
+	Best              //enumer:attr json=second
+)
+`
+	for _, test := range []struct {
+		json       string
+		decodeAny  bool
+		ignoreCase bool
+		expected   string // The end of the error, or "" if none.
+	}{
+		{"Best", false, false, ""},
+		{"Best", true, false, `/grade.go:5:2) are both named "Best"`},
+		{"best", true, false, ""},
+		{"best", true, true, `/grade.go:5:2) are both named "best" ignoring case`},
+	} {
+		got = ""
+		g := Generator{jsonNames: "attr:json", decodeAny: test.decodeAny}
+		typeName := loadGolden(t, &g, Golden{"grade", fmt.Sprintf(input, test.json), ""})
+		g.generate(typeName, false, false, false, false, "noop", "", false, test.ignoreCase, false, "")
+		switch {
+		case test.expected == "" && got != "":
+			t.Errorf("%+v: unexpected error %s", test, got)
+		case test.expected != "" && (!strings.HasPrefix(got, "names of Grade collide under -decodeany:") || !strings.HasSuffix(got, test.expected)):
+			t.Errorf("%+v: got error %q; expected one ending with %q", test, got, test.expected)
+		}
+	}
+}
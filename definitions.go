@@ -0,0 +1,171 @@
+package main
+
+import (
+	"fmt"
+	"go/token"
+	"io/ioutil"
+	"log"
+	"math/big"
+	"strconv"
+
+	yamlenc "gopkg.in/yaml.v3"
+)
+
+// definitionFile is the layout of a -from file. Being YAML, it may be written
+// as JSON as well.
+type definitionFile struct {
+	Types []typeDefinition `yaml:"types"`
+}
+
+// typeDefinition declares an enum type.
+type typeDefinition struct {
+	Name        string            `yaml:"name"`
+	Type        string            `yaml:"type"` // Underlying integer type; int if empty.
+	Description string            `yaml:"description"`
+	Values      []valueDefinition `yaml:"values"`
+}
+
+// valueDefinition declares a constant of an enum type.
+type valueDefinition struct {
+	Name        string   `yaml:"name"`
+	Value       *string  `yaml:"value"` // An integer of the type; the previous value plus one if missing, as with iota.
+	Description string   `yaml:"description"`
+	Aliases     []string `yaml:"aliases"`    // Other constants with the same value.
+	Deprecated  string   `yaml:"deprecated"` // Deprecation notice, or "true".
+}
+
+// readDefinitions parses the named definition file.
+func readDefinitions(fileName string) []typeDefinition {
+	src, err := ioutil.ReadFile(fileName)
+	if err != nil {
+		log.Fatal(err)
+	}
+	var file definitionFile
+	if err := yamlenc.Unmarshal(src, &file); err != nil {
+		log.Fatalf("%s: %s", fileName, err)
+	}
+	if len(file.Types) == 0 {
+		log.Fatalf("%s: no types defined", fileName)
+	}
+	return file.Types
+}
+
+// definitionTypeNames returns the names of the types in the named definition
+// file.
+func definitionTypeNames(fileName string) []string {
+	var names []string
+	for _, def := range readDefinitions(fileName) {
+		names = append(names, def.Name)
+	}
+	return names
+}
+
+// defineFromFile defines the named types from the definition file.
+func (g *Generator) defineFromFile(fileName string, types []string) {
+	defs := make(map[string]typeDefinition)
+	for _, def := range readDefinitions(fileName) {
+		defs[def.Name] = def
+	}
+	for _, typeName := range types {
+		def, ok := defs[typeName]
+		if !ok {
+			log.Fatalf("%s: type %s is not defined", fileName, typeName)
+		}
+		dt, err := newDefinedType(def)
+		if err != nil {
+			log.Fatalf("%s: %s", fileName, err)
+		}
+		g.define(typeName, dt)
+	}
+}
+
+// newDefinedType validates the definition of a type and turns it into
+// values ready for the generator.
+func newDefinedType(def typeDefinition) (*definedType, error) {
+	if !token.IsIdentifier(def.Name) {
+		return nil, fmt.Errorf("invalid type name %q", def.Name)
+	}
+	underlying := def.Type
+	if underlying == "" {
+		underlying = "int"
+	}
+	var bits int
+	signed := true
+	switch underlying {
+	case "int", "int64":
+		bits = 64
+	case "int8", "int16", "int32":
+		bits, _ = strconv.Atoi(underlying[3:])
+	case "uint", "uint64", "uintptr":
+		bits, signed = 64, false
+	case "uint8", "uint16", "uint32":
+		bits, _ = strconv.Atoi(underlying[4:])
+		signed = false
+	default:
+		return nil, fmt.Errorf("%s: underlying type %q is not an integer type", def.Name, underlying)
+	}
+
+	dt := &definedType{underlying: underlying, doc: def.Description}
+	if len(def.Values) == 0 {
+		return nil, fmt.Errorf("no values defined for type %s", def.Name)
+	}
+	declared := make(map[string]bool)
+	next := new(big.Int)
+	for _, vd := range def.Values {
+		if vd.Value != nil {
+			if _, ok := next.SetString(*vd.Value, 0); !ok {
+				return nil, fmt.Errorf("%s: value %q of %s is not an integer", def.Name, *vd.Value, vd.Name)
+			}
+		}
+		if !fitsInt(next, bits, signed) {
+			return nil, fmt.Errorf("%s: value %s of %s overflows %s", def.Name, next, vd.Name, underlying)
+		}
+		n := next.Uint64()
+		if next.Sign() < 0 {
+			n = uint64(next.Int64())
+		}
+		doc := vd.Description
+		if vd.Deprecated != "" {
+			notice := "Deprecated: " + vd.Deprecated
+			if vd.Deprecated == "true" {
+				notice = "Deprecated: do not use."
+			}
+			if doc != "" {
+				doc += "\n\n"
+			}
+			doc += notice
+		}
+		for i, constName := range append([]string{vd.Name}, vd.Aliases...) {
+			if !token.IsIdentifier(constName) || constName == "_" {
+				return nil, fmt.Errorf("%s: invalid value name %q", def.Name, constName)
+			}
+			if declared[constName] {
+				return nil, fmt.Errorf("%s: value %s is declared twice", def.Name, constName)
+			}
+			declared[constName] = true
+			v := Value{
+				constName: constName,
+				name:      constName,
+				value:     n,
+				signed:    signed,
+				str:       next.String(),
+				doc:       doc,
+			}
+			if i > 0 {
+				v.doc = fmt.Sprintf("%s is an alias for %s.", constName, vd.Name)
+			}
+			dt.values = append(dt.values, v)
+		}
+		next.Add(next, big.NewInt(1))
+	}
+	return dt, nil
+}
+
+// fitsInt reports whether v is representable by an integer type of the given
+// size and signedness.
+func fitsInt(v *big.Int, bits int, signed bool) bool {
+	if signed {
+		return v.BitLen() < bits || v.Sign() < 0 && new(big.Int).Add(v, big.NewInt(1)).BitLen() < bits
+	}
+	return v.Sign() >= 0 && v.BitLen() <= bits
+}
@@ -0,0 +1,133 @@
+// This file contains tests for the definition files read by -from.
+
+package main
+
+import (
+	"strings"
+	"testing"
+
+	yamlenc "gopkg.in/yaml.v3"
+)
+
+const statusDefinitions = `
+types:
+  - name: Status
+    type: uint8
+    description: Status is the state of a job.
+    values:
+      - name: Pending
+        description: Pending jobs wait for a worker.
+        aliases: [Queued]
+      - name: Running
+      - name: Done
+        value: 5
+      - name: Failed
+        deprecated: use Done
+`
+
+// statusDefinitionsIn is the Go source equivalent to statusDefinitions.
+const statusDefinitionsIn = `// Status is the state of a job.
+type Status uint8
+
+const (
+	// Pending jobs wait for a worker.
+	Pending Status = 0
+	// Queued is an alias for Pending.
+	Queued  Status = 0
+	Running Status = 1
+	Done    Status = 5
+	// Deprecated: use Done
+	Failed Status = 6
+)
+`
+
+func TestDefinitionsMatchGoSource(t *testing.T) {
+	var file definitionFile
+	if err := yamlenc.Unmarshal([]byte(statusDefinitions), &file); err != nil {
+		t.Fatal(err)
+	}
+	def, err := newDefinedType(file.Types[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	fromDefs := Generator{pkg: &Package{name: "test"}}
+	fromDefs.define("Status", def)
+	fromDefs.generate("Status", true, false, false, false, "snake", "", false, false, false, "")
+	got := string(fromDefs.format())
+
+	var fromGo Generator
+	loadGolden(t, &fromGo, Golden{"status definitions", statusDefinitionsIn, ""})
+	fromGo.generate("Status", true, false, false, false, "snake", "", false, false, false, "")
+	expected := "\n" + statusDefinitionsIn + string(fromGo.format())
+	if got != expected {
+		t.Errorf("got\n====\n%s====\nexpected\n====\n%s", got, expected)
+	}
+}
+
+var badDefinitions = []struct {
+	def typeDefinition
+	err string
+}{
+	{typeDefinition{Name: "1Status"}, "invalid type name"},
+	{typeDefinition{Name: "Status", Type: "string", Values: []valueDefinition{{Name: "A"}}}, "not an integer type"},
+	{typeDefinition{Name: "Status"}, "no values defined"},
+	{typeDefinition{Name: "Status", Type: "uint8", Values: []valueDefinition{{Name: "A", Value: &[]string{"0"}[0]}, {Name: "B", Value: &[]string{"256"}[0]}}}, "overflows uint8"},
+	{typeDefinition{Name: "Status", Type: "uint", Values: []valueDefinition{{Name: "A", Value: &[]string{"-1"}[0]}}}, "overflows uint"},
+	{typeDefinition{Name: "Status", Type: "uint64", Values: []valueDefinition{{Name: "A", Value: &[]string{"0xffffffffffffffff"}[0]}, {Name: "B"}}}, "value 18446744073709551616 of B overflows uint64"},
+	{typeDefinition{Name: "Status", Type: "int8", Values: []valueDefinition{{Name: "A", Value: &[]string{"-129"}[0]}}}, "overflows int8"},
+	{typeDefinition{Name: "Status", Type: "int8", Values: []valueDefinition{{Name: "A", Value: &[]string{"127"}[0]}, {Name: "B"}}}, "value 128 of B overflows int8"},
+	{typeDefinition{Name: "Status", Values: []valueDefinition{{Name: "A", Value: &[]string{"1.5"}[0]}}}, `value "1.5" of A is not an integer`},
+	{typeDefinition{Name: "Status", Values: []valueDefinition{{Name: "A"}, {Name: "B", Aliases: []string{"A"}}}}, "declared twice"},
+	{typeDefinition{Name: "Status", Values: []valueDefinition{{Name: "a-b"}}}, "invalid value name"},
+}
+
+func TestBadDefinitions(t *testing.T) {
+	for _, test := range badDefinitions {
+		_, err := newDefinedType(test.def)
+		if err == nil || !strings.Contains(err.Error(), test.err) {
+			t.Errorf("%+v: got error %v; expected %q", test.def, err, test.err)
+		}
+	}
+}
+
+// TestDefinitionsUnsigned checks that the values of unsigned types decode past
+// the largest int64, and those of signed types down to the smallest one.
+func TestDefinitionsUnsigned(t *testing.T) {
+	const defs = `
+types:
+  - name: Mask
+    type: uint64
+    values:
+      - name: None
+        value: 0
+      - name: Top
+        value: 0x8000000000000000
+      - name: All
+        value: 18446744073709551615
+  - name: Level
+    type: int64
+    values:
+      - name: Lowest
+        value: -9223372036854775808
+      - name: Highest
+        value: 9223372036854775807
+`
+	var file definitionFile
+	if err := yamlenc.Unmarshal([]byte(defs), &file); err != nil {
+		t.Fatal(err)
+	}
+	for i, expected := range [][]Value{
+		{{value: 0, str: "0"}, {value: 1 << 63, str: "9223372036854775808"}, {value: 1<<64 - 1, str: "18446744073709551615"}},
+		{{value: 1 << 63, str: "-9223372036854775808", signed: true}, {value: 1<<63 - 1, str: "9223372036854775807", signed: true}},
+	} {
+		def, err := newDefinedType(file.Types[i])
+		if err != nil {
+			t.Fatal(err)
+		}
+		for j, v := range def.values {
+			if v.value != expected[j].value || v.str != expected[j].str || v.signed != expected[j].signed {
+				t.Errorf("%s: got value %d (%s, signed %t); expected %d (%s, signed %t)", v.constName, v.value, v.str, v.signed, expected[j].value, expected[j].str, expected[j].signed)
+			}
+		}
+	}
+}
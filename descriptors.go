@@ -0,0 +1,96 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+)
+
+// deprecationNotice splits a doc comment into its description and the
+// paragraph starting with "Deprecated:", if any.
+func deprecationNotice(doc string) (description, notice string) {
+	paragraphs := strings.Split(doc, "\n\n")
+	for i, p := range paragraphs {
+		if strings.HasPrefix(p, "Deprecated:") {
+			rest := append(paragraphs[:i:i], paragraphs[i+1:]...)
+			return strings.TrimSpace(strings.Join(rest, "\n\n")), p
+		}
+	}
+	return doc, ""
+}
+
+// deprecated reports whether the value is deprecated: the doc comment of the
+// constant has a "Deprecated:" paragraph, and so have the ones of its
+// aliases, so that renaming a constant does not retire its value.
+func (v *Value) deprecated() bool {
+	_, notice := deprecationNotice(v.doc)
+	return notice != "" && !v.liveAlias
+}
+
+// buildDescriptors writes the Description method, from the doc comments of
+// the constants, and the function listing a descriptor per value.
+func (g *Generator) buildDescriptors(runs [][]Value, typeName string) {
+	g.Printf(descriptorType, typeName)
+	g.Printf("\nvar _%sDescriptors = []%sDescriptor{\n", typeName, typeName)
+	for _, values := range runs {
+		for _, value := range values {
+			description, _ := deprecationNotice(value.doc)
+			g.Printf("\t{Name: %q, Value: %s", value.name, &value)
+			if description != "" {
+				g.Printf(", Description: %q", description)
+			}
+			if value.deprecated() {
+				g.Printf(", Deprecated: true")
+			}
+			if len(value.aliases) > 0 {
+				g.Printf(", Aliases: []string{%s}", quoteAll(value.aliases))
+			}
+			g.Printf("},\n")
+		}
+	}
+	g.Printf("}\n")
+
+	g.Printf("\nvar _%sDescriptionMap = map[%s]string{\n", typeName, typeName)
+	for _, values := range runs {
+		for _, value := range values {
+			if description, _ := deprecationNotice(value.doc); description != "" {
+				g.Printf("\t%s: %q,\n", &value, description)
+			}
+		}
+	}
+	g.Printf("}\n")
+	g.Printf(descriptorMethods, typeName)
+}
+
+// quoteAll returns the strings as a list of Go string literals.
+func quoteAll(ss []string) string {
+	quoted := make([]string, len(ss))
+	for i, s := range ss {
+		quoted[i] = fmt.Sprintf("%q", s)
+	}
+	return strings.Join(quoted, ", ")
+}
+
+// Argument to format is the type name.
+const descriptorType = `
+// %[1]sDescriptor describes a value of %[1]s.
+type %[1]sDescriptor struct {
+	Name        string   // The string representation of the value.
+	Value       %[1]s   // The constant.
+	Description string   // The doc comment of the constant, without the deprecation notice.
+	Deprecated  bool     // Whether the doc comment has a "Deprecated:" paragraph.
+	Aliases     []string // Other constants declared with the same value.
+}
+`
+
+// Argument to format is the type name.
+const descriptorMethods = `
+// %[1]sDescriptors returns a descriptor for each value of the enum
+func %[1]sDescriptors() []%[1]sDescriptor {
+	return _%[1]sDescriptors
+}
+
+// Description returns the doc comment of the constant declaring i, or "" if there is none
+func (i %[1]s) Description() string {
+	return _%[1]sDescriptionMap[i]
+}
+`
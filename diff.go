@@ -0,0 +1,243 @@
+package main
+
+import (
+	jsonenc "encoding/json"
+	"go/ast"
+	"log"
+	"os"
+	"sort"
+	"strings"
+
+	"golang.org/x/tools/go/packages"
+)
+
+// enumChange is an incompatible change between two versions of an enum,
+// printed as a line of JSON by "enumer diff".
+type enumChange struct {
+	Kind        string `json:"kind"` // One of the change kinds below.
+	Type        string `json:"type"`
+	Constant    string `json:"constant,omitempty"`
+	NewConstant string `json:"new_constant,omitempty"` // The new name of a renamed constant.
+	OldValue    string `json:"old_value,omitempty"`
+	NewValue    string `json:"new_value,omitempty"`
+	OldName     string `json:"old_name,omitempty"` // The string representation of the value.
+	NewName     string `json:"new_name,omitempty"`
+	Format      string `json:"format,omitempty"` // The name set, JSON or SQL, of a wire-name change, if not String.
+}
+
+// The kinds of changes, in the order they are reported.
+const (
+	changeTypeRemoved = "type-removed" // The type is no longer generated.
+	changeRemoved     = "removed"      // The constant and its value are gone.
+	changeRenamed     = "renamed"      // The constant is gone, a new one has its value.
+	changeRenumbered  = "renumbered"   // The constant has another value.
+	changeWireName    = "wire-name"    // The string representation of the value changed.
+)
+
+var changeOrder = map[string]int{changeTypeRemoved: 0, changeRemoved: 1, changeRenamed: 2, changeRenumbered: 3, changeWireName: 4}
+
+// runDiff implements "enumer diff old-dir new-dir": it reports the changes
+// between the types generated in the packages of the directories that break
+// the values stored or sent by the old version, and exits with status 1 if
+// there are any.
+func runDiff(args []string) {
+	if len(args) != 2 {
+		log.Fatalf("diff takes the directories of the old and the new version of a package")
+	}
+	changes := diffEnums(loadEnumVersion(args[0]), loadEnumVersion(args[1]))
+	enc := jsonenc.NewEncoder(os.Stdout)
+	for _, c := range changes {
+		if err := enc.Encode(c); err != nil {
+			log.Fatal(err)
+		}
+	}
+	if len(changes) > 0 {
+		os.Exit(1)
+	}
+}
+
+// loadEnumVersion loads the package in dir and returns the constants of each
+// type generated by enumer in it, named as the recorded flags of the
+// generated file name them. Types declared from other sources than Go code
+// are left out.
+func loadEnumVersion(dir string) map[string][]Value {
+	cfg := &packages.Config{
+		Mode: packages.LoadSyntax,
+		Dir:  dir,
+	}
+	pkgs, err := packages.Load(cfg, ".")
+	if err != nil {
+		log.Fatal(err)
+	}
+	if len(pkgs) != 1 {
+		log.Fatalf("%s: %d packages found", dir, len(pkgs))
+	}
+	if len(pkgs[0].Errors) > 0 {
+		log.Fatalf("%s: %s", dir, pkgs[0].Errors[0])
+	}
+	var g Generator
+	g.addPackage(pkgs[0])
+
+	enums := make(map[string][]Value)
+	for _, file := range g.pkg.files {
+		args, ok := generatedCommand(file.file)
+		if !ok || len(args) > 0 && (args[0] == "import-c" || args[0] == "import-proto") {
+			continue
+		}
+		if err := setFlags(args); err != nil {
+			log.Fatalf("%s: enumer %s: %s", dir, commandLine(args), err)
+		}
+		if *fromFile != "" || *typeNames == "" {
+			continue
+		}
+		g.acronyms, g.nameRules = nil, nil
+		g.jsonNames, g.sqlNames = *jsonNames, *sqlNames
+		if err := checkNameSetSpec(g.jsonNames); err != nil {
+			log.Fatalf("%s: -jsonnames: %s", dir, err)
+		}
+		if err := checkNameSetSpec(g.sqlNames); err != nil {
+			log.Fatalf("%s: -sqlnames: %s", dir, err)
+		}
+		if *acronyms != "" {
+			g.acronyms = strings.Split(*acronyms, ",")
+		}
+		for _, rule := range transformRules {
+			r, err := parseNameRule(rule, g.acronyms)
+			if err != nil {
+				log.Fatalf("%s: -transform-rule: %s", dir, err)
+			}
+			g.nameRules = append(g.nameRules, r)
+		}
+		for _, typeName := range strings.Split(*typeNames, ",") {
+			enums[typeName] = g.namedValues(typeName)
+		}
+	}
+	return enums
+}
+
+// namedValues returns the constants of the type, named by the pipeline of
+// generate as configured by the flags, along with their names in the name
+// sets.
+func (g *Generator) namedValues(typeName string) []Value {
+	var values []Value
+	for _, file := range g.pkg.files {
+		file.typeName = typeName
+		file.values = nil
+		ast.Inspect(file.file, file.genDecl)
+		values = append(values, file.values...)
+	}
+	var sets []*nameSet
+	if g.jsonNames != "" {
+		sets = append(sets, g.newNameSet(typeName, "JSON", g.jsonNames, values, *trimPrefix, CaseNone))
+	}
+	if g.sqlNames != "" {
+		sets = append(sets, g.newNameSet(typeName, "SQL", g.sqlNames, values, *trimPrefix, CaseNone))
+	}
+	for i := range values {
+		for _, set := range sets {
+			if values[i].setNames == nil {
+				values[i].setNames = make(map[string]string)
+			}
+			values[i].setNames[set.format] = set.names[values[i].value]
+		}
+	}
+	g.trimValueNames(values, *trimPrefix)
+	g.transformValueNames(values, *transformMethod, *empty)
+	if *lineComment {
+		g.replaceValuesWithLineComment(values)
+	}
+	return values
+}
+
+// diffEnums returns the incompatible changes from the old to the new
+// constants of the types, sorted by type and kind.
+func diffEnums(oldEnums, newEnums map[string][]Value) []enumChange {
+	var changes []enumChange
+	for typeName, oldValues := range oldEnums {
+		newValues, ok := newEnums[typeName]
+		if !ok {
+			changes = append(changes, enumChange{Kind: changeTypeRemoved, Type: typeName})
+			continue
+		}
+		byName := make(map[string]Value)
+		byValue := make(map[string][]Value)
+		for _, v := range newValues {
+			byName[v.constName] = v
+			byValue[v.str] = append(byValue[v.str], v)
+		}
+		oldByName := make(map[string]bool)
+		for _, v := range oldValues {
+			oldByName[v.constName] = true
+		}
+		for _, old := range oldValues {
+			c := enumChange{Type: typeName, Constant: old.constName, OldValue: old.str, OldName: old.name}
+			v, ok := byName[old.constName]
+			if ok && v.str == old.str {
+				changes = append(changes, setNameChanges(typeName, old, v)...)
+			}
+			switch {
+			case ok && v.str != old.str:
+				c.Kind, c.NewValue = changeRenumbered, v.str
+				if v.name != old.name {
+					c.NewName = v.name
+				}
+			case ok && v.name != old.name:
+				c.Kind, c.NewName = changeWireName, v.name
+			case ok:
+				continue
+			default:
+				// Look for a new constant with the value.
+				c.Kind = changeRemoved
+				for _, v := range byValue[old.str] {
+					if oldByName[v.constName] {
+						continue
+					}
+					c.Kind, c.NewConstant = changeRenamed, v.constName
+					if v.name != old.name {
+						c.NewName = v.name
+					}
+					break
+				}
+			}
+			changes = append(changes, c)
+		}
+	}
+	sort.SliceStable(changes, func(i, j int) bool {
+		a, b := changes[i], changes[j]
+		if a.Type != b.Type {
+			return a.Type < b.Type
+		}
+		if a.Kind != b.Kind {
+			return changeOrder[a.Kind] < changeOrder[b.Kind]
+		}
+		if a.Constant != b.Constant {
+			return a.Constant < b.Constant
+		}
+		return a.Format < b.Format
+	})
+	return changes
+}
+
+// setNameChanges returns the wire-name changes of the constant in the name
+// sets of either version. A version without the set names the constant as
+// String does.
+func setNameChanges(typeName string, old, v Value) []enumChange {
+	var changes []enumChange
+	for _, format := range []string{"JSON", "SQL"} {
+		oldName, inOld := old.setNames[format]
+		newName, inNew := v.setNames[format]
+		if !inOld && !inNew {
+			continue
+		}
+		if !inOld {
+			oldName = old.name
+		}
+		if !inNew {
+			newName = v.name
+		}
+		if newName != oldName {
+			changes = append(changes, enumChange{Kind: changeWireName, Type: typeName, Constant: old.constName, OldValue: old.str, OldName: oldName, NewName: newName, Format: format})
+		}
+	}
+	return changes
+}
@@ -0,0 +1,41 @@
+// This file contains tests for the report of "enumer diff".
+
+package main
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestDiffEnums(t *testing.T) {
+	oldEnums := map[string][]Value{
+		"Day": {
+			{constName: "DayMonday", name: "monday", str: "0", setNames: map[string]string{"JSON": "mon"}},
+			{constName: "DayTuesday", name: "tuesday", str: "1"},
+			{constName: "DayWed", name: "wed", str: "2"},
+			{constName: "DayThursday", name: "thursday", str: "3"},
+			{constName: "DayFriday", name: "friday", str: "4"},
+		},
+		"Month": {{constName: "January", name: "January", str: "1"}},
+	}
+	newEnums := map[string][]Value{
+		"Day": {
+			{constName: "DayMonday", name: "monday", str: "0", setNames: map[string]string{"JSON": "monday"}},
+			{constName: "DayTuesday", name: "TUESDAY", str: "1", setNames: map[string]string{"SQL": "tue"}},
+			{constName: "DayWednesday", name: "wednesday", str: "2"},
+			{constName: "DayThursday", name: "thursday", str: "4"},
+		},
+	}
+	expected := []enumChange{
+		{Kind: changeRemoved, Type: "Day", Constant: "DayFriday", OldValue: "4", OldName: "friday"},
+		{Kind: changeRenamed, Type: "Day", Constant: "DayWed", NewConstant: "DayWednesday", OldValue: "2", OldName: "wed", NewName: "wednesday"},
+		{Kind: changeRenumbered, Type: "Day", Constant: "DayThursday", OldValue: "3", NewValue: "4", OldName: "thursday"},
+		{Kind: changeWireName, Type: "Day", Constant: "DayMonday", OldValue: "0", OldName: "mon", NewName: "monday", Format: "JSON"},
+		{Kind: changeWireName, Type: "Day", Constant: "DayTuesday", OldValue: "1", OldName: "tuesday", NewName: "TUESDAY"},
+		{Kind: changeWireName, Type: "Day", Constant: "DayTuesday", OldValue: "1", OldName: "tuesday", NewName: "tue", Format: "SQL"},
+		{Kind: changeTypeRemoved, Type: "Month"},
+	}
+	if got := diffEnums(oldEnums, newEnums); !reflect.DeepEqual(got, expected) {
+		t.Errorf("got %+v\nexpected %+v", got, expected)
+	}
+}
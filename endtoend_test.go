@@ -49,6 +49,10 @@ func TestEndToEnd(t *testing.T) {
 	}
 	// Generate, compile, and run the test programs.
 	for _, name := range names {
+		if name == "src" {
+			// Packages for the analyzer tests.
+			continue
+		}
 		if !strings.HasSuffix(name, ".go") {
 			t.Errorf("%s is not a Go file", name)
 			continue
@@ -65,6 +69,11 @@ func TestEndToEnd(t *testing.T) {
 			typeName = "CamelCaseValue"
 			transformNameMethod = "snake"
 		}
+		// transform_<method>.go checks the names given by the transform method.
+		if base := strings.TrimSuffix(name, ".go"); strings.HasPrefix(base, "transform_") {
+			typeName = "CamelCaseValue"
+			transformNameMethod = strings.TrimPrefix(base, "transform_")
+		}
 
 		stringerCompileAndRun(t, dir, stringer, typeName, name, transformNameMethod)
 	}
@@ -122,4 +131,60 @@ func runInDir(dir, name string, arg ...string) error {
 	cmd.Stdout = os.Stdout
 	cmd.Stderr = os.Stderr
 	return cmd.Run()
-}
\ No newline at end of file
+}
+// TestEndToEndPlatforms checks that rerunning stringer with -platforms removes
+// the outputs of the previous run that no longer apply, as the values of the
+// platforms become different and then identical again.
+func TestEndToEndPlatforms(t *testing.T) {
+	dir, err := ioutil.TempDir("", "stringer")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	stringer := filepath.Join(dir, "stringer.exe")
+	if err := run("go", "build", "-o", stringer); err != nil {
+		t.Fatalf("building stringer: %s", err)
+	}
+	pkg := filepath.Join(dir, "size")
+	if err := os.Mkdir(pkg, 0755); err != nil {
+		t.Fatal(err)
+	}
+	write := func(name, src string) {
+		if err := ioutil.WriteFile(filepath.Join(pkg, name), []byte(src), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	write("go.mod", "module size\n\ngo 1.16\n")
+	write("size.go", "package size\n\ntype Size int\n\nconst Small Size = 1\n")
+	write("size_windows.go", "package size\n\nconst Big Size = 8\n")
+	write("size_linux.go", "package size\n\nconst Big Size = 8\n")
+	shared := []string{"size_string.go"}
+	perPlatform := []string{"size_string_linux_amd64.go", "size_string_windows_amd64.go"}
+	for _, test := range []struct {
+		windowsBig string
+		exist      []string
+		missing    []string
+	}{
+		{"8", shared, perPlatform},
+		{"4", perPlatform, shared},
+		{"8", shared, perPlatform},
+	} {
+		write("size_windows.go", "package size\n\nconst Big Size = "+test.windowsBig+"\n")
+		if err := runInDir(pkg, stringer, "-type", "Size", "-platforms", "linux/amd64,windows/amd64", "."); err != nil {
+			t.Fatal(err)
+		}
+		for _, name := range test.exist {
+			if _, err := os.Stat(filepath.Join(pkg, name)); err != nil {
+				t.Errorf("Big is %s on windows: %s", test.windowsBig, err)
+			}
+		}
+		for _, name := range test.missing {
+			if _, err := os.Stat(filepath.Join(pkg, name)); !os.IsNotExist(err) {
+				t.Errorf("Big is %s on windows: %s was not removed", test.windowsBig, name)
+			}
+		}
+		if err := runInDir(pkg, "go", "vet", "."); err != nil {
+			t.Errorf("Big is %s on windows: %s", test.windowsBig, err)
+		}
+	}
+}
@@ -5,12 +5,14 @@ import "fmt"
 // Arguments to format are:
 //	[1]: type name
 //      [2]: numeric value check code (or "")
+//      [3]: deprecated value hook code (or "")
+//      [4]: code checking the other name sets and the reserved values (or "")
 const stringNameToValueMethod = `// %[1]sFromString retrieves an enum value from the enum constants string name.
 // Throws an error if the param is not part of the enum.
 func %[1]sFromString(s string) (%[1]s, error) {
         if val, ok := _%[1]sNameToValueMap[s]; ok {
-                return val, nil
-        }%[2]s
+                %[3]sreturn val, nil
+        }%[2]s%[4]s
         return 0, fmt.Errorf("%%s does not belong to %[1]s values", s)
 }
 `
@@ -18,8 +20,8 @@ const stringIgnoreCaseNameToValueMethod = `// %[1]sFromString retrieves an enum
 // Throws an error if the param is not part of the enum.
 func %[1]sFromString(s string) (%[1]s, error) {
         if val, ok := _%[1]sNameToValueMapLowercase[strings.ToLower(s)]; ok {
-                return val, nil
-        }
+                %[3]sreturn val, nil
+        }%[4]s
         return 0, fmt.Errorf("%%s does not belong to %[1]s values", s)
 }
 `
@@ -27,8 +29,8 @@ const stringUpperNameToValueMethod = `// %[1]sFromString retrieves an enum value
 // Throws an error if the param is not part of the enum.
 func %[1]sFromString(s string) (%[1]s, error) {
         if val, ok := _%[1]sNameToValueMap[strings.ToUpper(s)]; ok {
-                return val, nil
-        }%[2]s
+                %[3]sreturn val, nil
+        }%[2]s%[4]s
         return 0, fmt.Errorf("%%s does not belong to %[1]s values", s)
 }
 `
@@ -36,20 +38,21 @@ const stringLowerNameToValueMethod = `// %[1]sFromString retrieves an enum value
 // Throws an error if the param is not part of the enum.
 func %[1]sFromString(s string) (%[1]s, error) {
         if val, ok := _%[1]sNameToValueMap[strings.ToLower(s)]; ok {
-                return val, nil
-        }%[2]s
+                %[3]sreturn val, nil
+        }%[2]s%[4]s
         return 0, fmt.Errorf("%%s does not belong to %[1]s values", s)
 }
 `
 
 // Arguments to format are:
 //      [1]: type name
+//      [2]: deprecated value hook code (or "")
 const stringNumericCheck = `
         i, err := strconv.Atoi(s)
         if err == nil {
                 for _, v := range _%[1]sNameToValueMap {
                         if int(v) == i {
-                                return v, nil
+                                %[2]sreturn v, nil
                         }
                 }
         }`
@@ -63,6 +66,31 @@ const (
 	CaseMixed
 )
 
+// Arguments to format are:
+//	[1]: type name
+//	[2]: decoded value
+const deprecatedHookCall = `if _, ok := _%[1]sDeprecatedMap[%[2]s]; ok && %[1]sDeprecatedHook != nil {
+                        %[1]sDeprecatedHook(%[2]s)
+                }
+                `
+
+// Arguments to format are:
+//	[1]: type name
+const deprecatedMethods = `
+// IsDeprecated reports whether the constant declaring i is deprecated.
+func (i %[1]s) IsDeprecated() bool {
+	_, ok := _%[1]sDeprecatedMap[i]
+	return ok
+}
+`
+
+// Arguments to format are:
+//	[1]: type name
+const deprecatedHookVar = `
+// %[1]sDeprecatedHook, if not nil, is called with each deprecated value decoded from a string.
+var %[1]sDeprecatedHook func(%[1]s)
+`
+
 // Arguments to format are:
 //	[1]: type name
 const stringValuesMethod = `// %[1]sValues returns all values of the enum
@@ -93,13 +121,48 @@ func (i %[1]s) IsA%[1]s() bool {
 }
 `
 
+// Arguments to format are:
+//	[1]: type name
+//	[2]: reserved number check code (or "")
+const fromIntMethod = `
+// %[1]sFromInt returns the value of %[1]s equal to i.
+// Throws an error if there is none, unlike a conversion.
+func %[1]sFromInt(i int) (%[1]s, error) {
+	if v := %[1]s(i); int(v) == i && v.IsA%[1]s() {
+		return v, nil
+	}
+	%[2]sreturn 0, fmt.Errorf("%%d does not belong to %[1]s values", i)
+}
+`
+
+// hookCall returns the call of the deprecated hook on the decoded variable,
+// or "" if there is no hook or no deprecated value.
+func (g *Generator) hookCall(runs [][]Value, typeName, variable string) string {
+	if !g.deprecatedHook {
+		return ""
+	}
+	for _, values := range runs {
+		for _, value := range values {
+			if value.deprecated() {
+				return fmt.Sprintf(deprecatedHookCall, typeName, variable)
+			}
+		}
+	}
+	return ""
+}
+
 func (g *Generator) buildBasicExtras(runs [][]Value, typeName string, runsThreshold int, ignoreCase CaseMatch, numeric bool) {
 	// At this moment, either "g.declareIndexAndNameVars()" or "g.declareNameVars()" has been called
 
-	// Print the slice of values
+	// Print the slice of values, leaving out the deprecated ones
+	var deprecated []Value
 	g.Printf("\nvar _%sValues = []%s{", typeName, typeName)
 	for _, values := range runs {
 		for _, value := range values {
+			if value.deprecated() {
+				deprecated = append(deprecated, value)
+				continue
+			}
 			g.Printf("\t%s, ", value.str)
 		}
 	}
@@ -145,20 +208,50 @@ func (g *Generator) buildBasicExtras(runs [][]Value, typeName string, runsThresh
 		g.Printf("}\n\n")
 	}
 
+	// Print the set of deprecated values, which decode but are not listed
+	if len(deprecated) > 0 {
+		g.Printf("\nvar _%sDeprecatedMap = map[%s]struct{}{\n", typeName, typeName)
+		for _, value := range deprecated {
+			g.Printf("\t%s: {},\n", &value)
+		}
+		g.Printf("}\n")
+		g.Printf(deprecatedMethods, typeName)
+	}
+	if g.deprecatedHook {
+		g.Printf(deprecatedHookVar, typeName)
+	}
+	hookCall := g.hookCall(runs, typeName, "val")
+
+	// Print the reserved numbers and names, which fail to decode with their own error,
+	// after the names of the other sets if they decode too
+	otherNamesCheck, reservedCheck, fromIntCheck := "", "", ""
+	if g.decodeAny {
+		otherNamesCheck = g.anyNameCheck(runs, typeName, ignoreCase)
+	}
+	if g.reserved != nil {
+		reservedCheck = g.buildReserved(g.reserved, typeName, ignoreCase, numeric)
+		if len(g.reserved.ranges) > 0 {
+			fromIntCheck = fmt.Sprintf(reservedFromIntCheck, typeName)
+		}
+	}
+
 	// Print the basic extra methods
 	numCheck := ""
 	if numeric {
-		numCheck = fmt.Sprintf(stringNumericCheck, typeName)
+		numCheck = fmt.Sprintf(stringNumericCheck, typeName, g.hookCall(runs, typeName, "v"))
 	}
+	// The names of the sets decode alike after their own lookup.
+	g.decodeTail = numCheck + reservedCheck
+	otherNamesCheck += reservedCheck
 	switch ignoreCase {
 	case CaseLower:
-		g.Printf(stringLowerNameToValueMethod, typeName, numCheck)
+		g.Printf(stringLowerNameToValueMethod, typeName, numCheck, hookCall, otherNamesCheck)
 	case CaseUpper:
-		g.Printf(stringUpperNameToValueMethod, typeName, numCheck)
+		g.Printf(stringUpperNameToValueMethod, typeName, numCheck, hookCall, otherNamesCheck)
 	case CaseMixed:
-		g.Printf(stringIgnoreCaseNameToValueMethod, typeName, numCheck)
+		g.Printf(stringIgnoreCaseNameToValueMethod, typeName, numCheck, hookCall, otherNamesCheck)
 	default:
-		g.Printf(stringNameToValueMethod, typeName, numCheck)
+		g.Printf(stringNameToValueMethod, typeName, numCheck, hookCall, otherNamesCheck)
 	}
 
 	g.Printf(stringValuesMethod, typeName)
@@ -167,14 +260,19 @@ func (g *Generator) buildBasicExtras(runs [][]Value, typeName string, runsThresh
 	} else { // There is a map of values, the code is simpler then
 		g.Printf(stringBelongsMethodSet, typeName)
 	}
+	if g.fromInt {
+		g.Printf(fromIntMethod, typeName, fromIntCheck)
+	}
 }
 
 // Arguments to format are:
 //	[1]: type name
+//	[2]: method returning the name to marshal
+//	[3]: function decoding the name
 const jsonMethods = `
 // MarshalJSON implements the json.Marshaler interface for %[1]s
 func (i %[1]s) MarshalJSON() ([]byte, error) {
-	return json.Marshal(i.String())
+	return json.Marshal(i.%[2]s())
 }
 
 // UnmarshalJSON implements the json.Unmarshaler interface for %[1]s
@@ -185,13 +283,17 @@ func (i *%[1]s) UnmarshalJSON(data []byte) error {
 	}
 
 	var err error
-	*i, err = %[1]sString(s)
+	*i, err = %[3]s(s)
 	return err
 }
 `
 
 func (g *Generator) buildJSONMethods(runs [][]Value, typeName string, runsThreshold int) {
-	g.Printf(jsonMethods, typeName)
+	if g.nameSetFor("JSON") != nil {
+		g.Printf(jsonMethods, typeName, "JSONName", typeName+"FromJSONName")
+		return
+	}
+	g.Printf(jsonMethods, typeName, "String", typeName+"String")
 }
 
 // Arguments to format are:
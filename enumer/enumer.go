@@ -0,0 +1,189 @@
+// Package enumer holds generic helpers working with any type generated by
+// the enumer tool with the -generic flag.
+//
+// Such types implement Enum through a generated EnumDescriptor method, e.g.
+//
+//	day, err := enumer.Parse[Day]("Monday")
+//	for _, d := range enumer.Values[Day]() {
+//		...
+//	}
+//	flag.Var(enumer.FlagValue(&day), "day", "day of the week")
+package enumer
+
+import (
+	"flag"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Descriptor gathers the generated functions of the enum type T.
+type Descriptor[T comparable] struct {
+	Name    string                  // The name of the type.
+	Values  func() []T              // Lists the values, e.g. DayValues.
+	Parse   func(string) (T, error) // Parses the name of a value, e.g. DayFromString.
+	IsValid func(T) bool            // Reports whether a value is declared, e.g. Day.IsADay.
+}
+
+// Enum is implemented by the types generated with the -generic flag. T is the
+// type itself.
+type Enum[T comparable] interface {
+	comparable
+	fmt.Stringer
+	EnumDescriptor() Descriptor[T]
+}
+
+// descriptor returns the descriptor of T.
+func descriptor[T Enum[T]]() Descriptor[T] {
+	var zero T
+	return zero.EnumDescriptor()
+}
+
+// Parse returns the value of T with the given name.
+func Parse[T Enum[T]](s string) (T, error) {
+	return descriptor[T]().Parse(s)
+}
+
+// Values returns the values of T.
+func Values[T Enum[T]]() []T {
+	return descriptor[T]().Values()
+}
+
+// Names returns the names of the values of T, in the order of Values.
+func Names[T Enum[T]]() []string {
+	values := Values[T]()
+	names := make([]string, len(values))
+	for i, v := range values {
+		names[i] = v.String()
+	}
+	return names
+}
+
+// IsValid reports whether v is a declared value of T.
+func IsValid[T Enum[T]](v T) bool {
+	return descriptor[T]().IsValid(v)
+}
+
+// Set is a set of values of T. The zero value is an empty set ready to use.
+// As a flag.Value, it accepts comma-separated lists of names.
+type Set[T Enum[T]] struct {
+	m map[T]struct{}
+}
+
+// NewSet returns a set holding the values.
+func NewSet[T Enum[T]](values ...T) *Set[T] {
+	s := new(Set[T])
+	s.Add(values...)
+	return s
+}
+
+// Add adds the values to the set.
+func (s *Set[T]) Add(values ...T) {
+	if s.m == nil {
+		s.m = make(map[T]struct{})
+	}
+	for _, v := range values {
+		s.m[v] = struct{}{}
+	}
+}
+
+// Remove removes the values from the set.
+func (s *Set[T]) Remove(values ...T) {
+	for _, v := range values {
+		delete(s.m, v)
+	}
+}
+
+// Contains reports whether v is in the set.
+func (s *Set[T]) Contains(v T) bool {
+	_, ok := s.m[v]
+	return ok
+}
+
+// Len returns the number of values in the set.
+func (s *Set[T]) Len() int {
+	return len(s.m)
+}
+
+// Values returns the values in the set, in the order of Values, followed by
+// the values not listed there ordered by name.
+func (s *Set[T]) Values() []T {
+	values := make([]T, 0, len(s.m))
+	listed := make(map[T]bool)
+	for _, v := range Values[T]() {
+		listed[v] = true
+		if s.Contains(v) {
+			values = append(values, v)
+		}
+	}
+	var others []T
+	for v := range s.m {
+		if !listed[v] {
+			others = append(others, v)
+		}
+	}
+	sort.Slice(others, func(i, j int) bool { return others[i].String() < others[j].String() })
+	return append(values, others...)
+}
+
+// String returns the comma-separated names of the values in the set.
+func (s *Set[T]) String() string {
+	if s == nil {
+		return ""
+	}
+	names := make([]string, 0, s.Len())
+	for _, v := range s.Values() {
+		names = append(names, v.String())
+	}
+	return strings.Join(names, ",")
+}
+
+// Set adds the values named in the comma-separated list, implementing
+// flag.Value.
+func (s *Set[T]) Set(list string) error {
+	for _, name := range strings.Split(list, ",") {
+		v, err := Parse[T](strings.TrimSpace(name))
+		if err != nil {
+			return err
+		}
+		s.Add(v)
+	}
+	return nil
+}
+
+// flagValue implements flag.Getter for a variable of type T.
+type flagValue[T Enum[T]] struct {
+	p *T
+}
+
+// FlagValue returns a flag.Value setting *p from the name of a value.
+func FlagValue[T Enum[T]](p *T) flag.Getter {
+	return flagValue[T]{p}
+}
+
+func (f flagValue[T]) String() string {
+	if f.p == nil {
+		return ""
+	}
+	return (*f.p).String()
+}
+
+func (f flagValue[T]) Set(s string) error {
+	v, err := Parse[T](s)
+	if err != nil {
+		return err
+	}
+	*f.p = v
+	return nil
+}
+
+func (f flagValue[T]) Get() interface{} {
+	return *f.p
+}
+
+// Var defines a flag of type T in fs, with the given default value. The usage
+// message lists the accepted names.
+func Var[T Enum[T]](fs *flag.FlagSet, p *T, name string, value T, usage string) {
+	*p = value
+	fs.Var(FlagValue(p), name, fmt.Sprintf("%s (one of %s)", usage, strings.Join(Names[T](), ", ")))
+}
@@ -0,0 +1,109 @@
+package enumer_test
+
+import (
+	"flag"
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/capsule8/enumer/enumer"
+)
+
+// Day is written the way generated files are.
+type Day int
+
+const (
+	Monday Day = iota
+	Tuesday
+	Wednesday
+)
+
+var dayNames = []string{"Monday", "Tuesday", "Wednesday"}
+
+func (d Day) String() string {
+	if d.IsADay() {
+		return dayNames[d]
+	}
+	return fmt.Sprintf("Day(%d)", int(d))
+}
+
+func DayValues() []Day { return []Day{Monday, Tuesday, Wednesday} }
+
+func DayFromString(s string) (Day, error) {
+	for i, name := range dayNames {
+		if name == s {
+			return Day(i), nil
+		}
+	}
+	return 0, fmt.Errorf("%s does not belong to Day values", s)
+}
+
+func (d Day) IsADay() bool { return d >= 0 && int(d) < len(dayNames) }
+
+var _DayDescriptor = enumer.Descriptor[Day]{Name: "Day", Values: DayValues, Parse: DayFromString, IsValid: Day.IsADay}
+
+func (Day) EnumDescriptor() enumer.Descriptor[Day] { return _DayDescriptor }
+
+func TestParseAndValues(t *testing.T) {
+	if d, err := enumer.Parse[Day]("Tuesday"); d != Tuesday || err != nil {
+		t.Errorf("got %v, %v; expected Tuesday", d, err)
+	}
+	if _, err := enumer.Parse[Day]("Sunday"); err == nil {
+		t.Errorf("Sunday parsed")
+	}
+	if got := enumer.Values[Day](); !reflect.DeepEqual(got, []Day{Monday, Tuesday, Wednesday}) {
+		t.Errorf("got values %v", got)
+	}
+	if got := enumer.Names[Day](); !reflect.DeepEqual(got, dayNames) {
+		t.Errorf("got names %v", got)
+	}
+	if enumer.IsValid(Day(3)) || !enumer.IsValid(Monday) {
+		t.Errorf("IsValid is wrong")
+	}
+}
+
+func TestSet(t *testing.T) {
+	s := enumer.NewSet(Wednesday, Day(9), Monday)
+	if got := s.String(); got != "Monday,Wednesday,Day(9)" {
+		t.Errorf("got %q", got)
+	}
+	s.Remove(Day(9))
+	if err := s.Set("Tuesday, Monday"); err != nil {
+		t.Fatal(err)
+	}
+	if got := s.Values(); !reflect.DeepEqual(got, []Day{Monday, Tuesday, Wednesday}) || s.Len() != 3 {
+		t.Errorf("got %v", got)
+	}
+	if err := s.Set("Sunday"); err == nil {
+		t.Errorf("Sunday added")
+	}
+	var zero enumer.Set[Day]
+	if zero.Contains(Monday) || zero.String() != "" {
+		t.Errorf("zero set is not empty")
+	}
+}
+
+func TestFlag(t *testing.T) {
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	fs.SetOutput(new(flagOutput))
+	var day Day
+	enumer.Var(fs, &day, "day", Tuesday, "day of the week")
+	var days enumer.Set[Day]
+	fs.Var(&days, "days", "days of the week")
+	if err := fs.Parse([]string{"-day", "Wednesday", "-days", "Monday,Tuesday"}); err != nil {
+		t.Fatal(err)
+	}
+	if day != Wednesday || days.String() != "Monday,Tuesday" {
+		t.Errorf("got %v and %v", day, &days)
+	}
+	if usage := fs.Lookup("day").Usage; usage != "day of the week (one of Monday, Tuesday, Wednesday)" {
+		t.Errorf("got usage %q", usage)
+	}
+	if err := fs.Parse([]string{"-day", "Sunday"}); err == nil {
+		t.Errorf("Sunday parsed")
+	}
+}
+
+type flagOutput struct{}
+
+func (flagOutput) Write(p []byte) (int, error) { return len(p), nil }
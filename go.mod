@@ -5,6 +5,7 @@ go 1.24
 require (
 	github.com/pascaldekloe/name v1.0.1
 	golang.org/x/tools v0.31.0
+	gopkg.in/yaml.v3 v3.0.1
 )
 
 require (
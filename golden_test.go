@@ -10,9 +10,14 @@
 package main
 
 import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"go/types"
 	"io/ioutil"
 	"os"
 	"path/filepath"
+	"reflect"
 	"strings"
 	"testing"
 )
@@ -1123,6 +1128,17 @@ func TestGolden(t *testing.T) {
 
 func runGoldenTest(t *testing.T, test Golden, generateJSON, generateYAML, generateSQL, generateText bool, prefix string) {
 	var g Generator
+	typeName := loadGolden(t, &g, test)
+	g.generate(typeName, generateJSON, generateYAML, generateSQL, generateText, "noop", prefix, false, false, false, "")
+	got := string(g.format())
+	if got != test.output {
+		t.Errorf("%s: got\n====\n%s====\nexpected\n====%s", test.name, got, test.output)
+	}
+}
+
+// loadGolden parses the input of the test case into the generator and
+// returns the name of the type it declares.
+func loadGolden(t *testing.T, g *Generator, test Golden) string {
 	input := "package test\n" + test.input
 	file := test.name + ".go"
 
@@ -1142,15 +1158,1748 @@ func runGoldenTest(t *testing.T, test Golden, generateJSON, generateYAML, genera
 	if err != nil {
 		t.Error(err)
 	}
-	g.parsePackage([]string{absFile})
-	// Extract the name and type of the constant from the first line.
-	tokens := strings.SplitN(test.input, " ", 3)
+	g.parsePackage([]string{absFile}, nil, "")
+	// Extract the name and type of the constant from the first line,
+	// skipping any doc comment.
+	decl := test.input
+	for strings.HasPrefix(decl, "//") {
+		decl = decl[strings.Index(decl, "\n")+1:]
+	}
+	tokens := strings.SplitN(decl, " ", 3)
 	if len(tokens) != 3 {
 		t.Fatalf("%s: need type declaration on first line", test.name)
 	}
-	g.generate(tokens[1], generateJSON, generateYAML, generateSQL, generateText, "noop", prefix, false, false, false, "")
+	return tokens[1]
+}
+
+const weekdayProtoIn = `type Weekday int
+const (
+	WeekdayMonday Weekday = iota + 1
+	WeekdayTuesday
+	WeekdayWednesday
+)
+`
+
+const weekdayProtoPb = `package pb
+
+type Weekday int32
+
+const (
+	Weekday_WEEKDAY_UNSPECIFIED Weekday = 0
+	Weekday_WEEKDAY_MONDAY      Weekday = 1
+	Weekday_WEEKDAY_TUESDAY     Weekday = 2
+	Weekday_WEEKDAY_WEDNESDAY   Weekday = 3
+)
+`
+
+const weekdayProtoOut = `
+const _WeekdayName = "WeekdayMondayWeekdayTuesdayWeekdayWednesday"
+
+var _WeekdayMap = map[Weekday]string{
+	1: _WeekdayName[0:13],
+	2: _WeekdayName[13:27],
+	3: _WeekdayName[27:43],
+}
+
+func (i Weekday) String() string {
+	if str, ok := _WeekdayMap[i]; ok {
+		return str
+	}
+	return fmt.Sprintf("Weekday(%d)", i)
+}
+
+var _WeekdayValues = []Weekday{1, 2, 3}
+
+var _WeekdayNameToValueMap = map[string]Weekday{
+	_WeekdayName[0:13]:  1,
+	_WeekdayName[13:27]: 2,
+	_WeekdayName[27:43]: 3,
+}
+
+// WeekdayFromString retrieves an enum value from the enum constants string name.
+// Throws an error if the param is not part of the enum.
+func WeekdayFromString(s string) (Weekday, error) {
+	if val, ok := _WeekdayNameToValueMap[s]; ok {
+		return val, nil
+	}
+	return 0, fmt.Errorf("%s does not belong to Weekday values", s)
+}
+
+// WeekdayValues returns all values of the enum
+func WeekdayValues() []Weekday {
+	return _WeekdayValues
+}
+
+// IsAWeekday returns "true" if the value is listed in the enum definition. "false" otherwise
+func (i Weekday) IsAWeekday() bool {
+	_, ok := _WeekdayMap[i]
+	return ok
+}
+
+var _WeekdayToProtoMap = map[Weekday]pb.Weekday{
+	1: pb.Weekday_WEEKDAY_MONDAY,
+	2: pb.Weekday_WEEKDAY_TUESDAY,
+	3: pb.Weekday_WEEKDAY_WEDNESDAY,
+}
+
+var _WeekdayFromProtoMap = map[pb.Weekday]Weekday{
+	pb.Weekday_WEEKDAY_MONDAY:    1,
+	pb.Weekday_WEEKDAY_TUESDAY:   2,
+	pb.Weekday_WEEKDAY_WEDNESDAY: 3,
+}
+
+// ToProto converts Weekday into its protoc-generated counterpart pb.Weekday
+func (i Weekday) ToProto() pb.Weekday {
+	if v, ok := _WeekdayToProtoMap[i]; ok {
+		return v
+	}
+	return pb.Weekday_WEEKDAY_UNSPECIFIED
+}
+
+// WeekdayFromProto converts a protoc-generated pb.Weekday into Weekday.
+// Throws an error if the param has no counterpart in the enum.
+func WeekdayFromProto(v pb.Weekday) (Weekday, error) {
+	if i, ok := _WeekdayFromProtoMap[v]; ok {
+		return i, nil
+	}
+	return 0, fmt.Errorf("%v does not belong to Weekday values", v)
+}
+`
+
+const weekdayProtoEnumOut = `
+enum Weekday {
+  WEEKDAY_UNSPECIFIED = 0;
+  WEEKDAY_MONDAY = 1;
+  WEEKDAY_TUESDAY = 2;
+  WEEKDAY_WEDNESDAY = 3;
+}
+`
+
+func TestGoldenProto(t *testing.T) {
+	fset := token.NewFileSet()
+	f, err := parser.ParseFile(fset, "pb.go", weekdayProtoPb, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pkg, err := new(types.Config).Check("example.com/pb", fset, []*ast.File{f}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pt, err := newProtoType(pkg, "Weekday")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	test := Golden{"weekday with proto", weekdayProtoIn, weekdayProtoOut}
+	g := Generator{protoEnums: true, protoTypes: map[string]*protoType{"Weekday": pt}}
+	typeName := loadGolden(t, &g, test)
+	g.generate(typeName, false, false, false, false, "noop", "", false, false, false, "")
+	got := string(g.format())
+	if got != test.output {
+		t.Errorf("%s: got\n====\n%s====\nexpected\n====%s", test.name, got, test.output)
+	}
+	if got := g.protoBuf.String(); got != weekdayProtoEnumOut {
+		t.Errorf("%s: got\n====\n%s====\nexpected\n====%s", test.name, got, weekdayProtoEnumOut)
+	}
+}
+
+const colorGQLIn = `type Color int
+const (
+	Red Color = iota
+	Green
+	Blue
+)
+`
+
+const colorGQLOut = `
+const _ColorName = "REDGREENBLUE"
+
+var _ColorMap = map[Color]string{
+	0: _ColorName[0:3],
+	1: _ColorName[3:8],
+	2: _ColorName[8:12],
+}
+
+func (i Color) String() string {
+	if str, ok := _ColorMap[i]; ok {
+		return str
+	}
+	return fmt.Sprintf("Color(%d)", i)
+}
+
+var _ColorValues = []Color{0, 1, 2}
+
+var _ColorNameToValueMap = map[string]Color{
+	_ColorName[0:3]:  0,
+	_ColorName[3:8]:  1,
+	_ColorName[8:12]: 2,
+}
+
+// ColorFromString retrieves an enum value from the enum constants string name.
+// Throws an error if the param is not part of the enum.
+func ColorFromString(s string) (Color, error) {
+	if val, ok := _ColorNameToValueMap[s]; ok {
+		return val, nil
+	}
+	return 0, fmt.Errorf("%s does not belong to Color values", s)
+}
+
+// ColorValues returns all values of the enum
+func ColorValues() []Color {
+	return _ColorValues
+}
+
+// IsAColor returns "true" if the value is listed in the enum definition. "false" otherwise
+func (i Color) IsAColor() bool {
+	_, ok := _ColorMap[i]
+	return ok
+}
+
+// MarshalGQL implements the graphql.Marshaler interface for Color
+func (i Color) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(i.String()))
+}
+
+// UnmarshalGQL implements the graphql.Unmarshaler interface for Color
+func (i *Color) UnmarshalGQL(value interface{}) error {
+	str, ok := value.(string)
+	if !ok {
+		return fmt.Errorf("Color should be a string, got %T", value)
+	}
+
+	var err error
+	*i, err = ColorFromString(str)
+	return err
+}
+`
+
+const colorGraphQLEnumOut = `
+enum Color {
+  RED
+  GREEN
+  BLUE
+}
+`
+
+func TestGoldenGraphQL(t *testing.T) {
+	test := Golden{"color with GraphQL", colorGQLIn, colorGQLOut}
+	g := Generator{gqlgen: true, graphqlEnums: true}
+	typeName := loadGolden(t, &g, test)
+	g.generate(typeName, false, false, false, false, "upper", "", false, false, false, "")
+	got := string(g.format())
+	if got != test.output {
+		t.Errorf("%s: got\n====\n%s====\nexpected\n====%s", test.name, got, test.output)
+	}
+	if got := g.graphqlBuf.String(); got != colorGraphQLEnumOut {
+		t.Errorf("%s: got\n====\n%s====\nexpected\n====%s", test.name, got, colorGraphQLEnumOut)
+	}
+}
+
+const statusSchemaIn = `// Status is the state of a job.
+type Status int
+const (
+	// Pending jobs wait for a worker.
+	Pending Status = iota
+	Running
+	// Done jobs have finished.
+	Done
+)
+`
+
+const statusSchemaOut = `
+const _StatusName = "pendingrunningdone"
+
+var _StatusMap = map[Status]string{
+	0: _StatusName[0:7],
+	1: _StatusName[7:14],
+	2: _StatusName[14:18],
+}
+
+func (i Status) String() string {
+	if str, ok := _StatusMap[i]; ok {
+		return str
+	}
+	return fmt.Sprintf("Status(%d)", i)
+}
+
+var _StatusValues = []Status{0, 1, 2}
+
+var _StatusNameToValueMap = map[string]Status{
+	_StatusName[0:7]:   0,
+	_StatusName[7:14]:  1,
+	_StatusName[14:18]: 2,
+}
+
+// StatusFromString retrieves an enum value from the enum constants string name.
+// Throws an error if the param is not part of the enum.
+func StatusFromString(s string) (Status, error) {
+	if val, ok := _StatusNameToValueMap[s]; ok {
+		return val, nil
+	}
+	return 0, fmt.Errorf("%s does not belong to Status values", s)
+}
+
+// StatusValues returns all values of the enum
+func StatusValues() []Status {
+	return _StatusValues
+}
+
+// IsAStatus returns "true" if the value is listed in the enum definition. "false" otherwise
+func (i Status) IsAStatus() bool {
+	_, ok := _StatusMap[i]
+	return ok
+}
+
+// MarshalJSON implements the json.Marshaler interface for Status
+func (i Status) MarshalJSON() ([]byte, error) {
+	return json.Marshal(i.String())
+}
+
+// UnmarshalJSON implements the json.Unmarshaler interface for Status
+func (i *Status) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return fmt.Errorf("Status should be a string, got %s", data)
+	}
+
+	var err error
+	*i, err = StatusString(s)
+	return err
+}
+
+const _StatusJSONSchema = "{\"title\":\"Status\",\"description\":\"Status is the state of a job.\",\"type\":\"string\",\"enum\":[\"pending\",\"running\",\"done\"],\"x-enum-varnames\":[\"Pending\",\"Running\",\"Done\"],\"x-enum-descriptions\":[\"Pending jobs wait for a worker.\",\"\",\"Done jobs have finished.\"]}"
+
+// JSONSchema returns the JSON Schema describing the values of Status
+func (Status) JSONSchema() json.RawMessage {
+	return json.RawMessage(_StatusJSONSchema)
+}
+`
+
+const statusSchemaDocumentOut = `{
+  "$schema": "https://json-schema.org/draft/2020-12/schema",
+  "title": "Status",
+  "description": "Status is the state of a job.",
+  "type": "string",
+  "enum": [
+    "pending",
+    "running",
+    "done"
+  ],
+  "x-enum-varnames": [
+    "Pending",
+    "Running",
+    "Done"
+  ],
+  "x-enum-descriptions": [
+    "Pending jobs wait for a worker.",
+    "",
+    "Done jobs have finished."
+  ]
+}
+`
+
+const statusOpenAPIOut = `{
+  "components": {
+    "schemas": {
+      "Status": {
+        "title": "Status",
+        "description": "Status is the state of a job.",
+        "type": "string",
+        "enum": [
+          "pending",
+          "running",
+          "done"
+        ],
+        "x-enum-varnames": [
+          "Pending",
+          "Running",
+          "Done"
+        ],
+        "x-enum-descriptions": [
+          "Pending jobs wait for a worker.",
+          "",
+          "Done jobs have finished."
+        ]
+      }
+    }
+  }
+}
+`
+
+func TestGoldenJSONSchema(t *testing.T) {
+	test := Golden{"status with JSON Schema", statusSchemaIn, statusSchemaOut}
+	g := Generator{jsonSchemas: true, openAPI: true}
+	typeName := loadGolden(t, &g, test)
+	g.generate(typeName, true, false, false, false, "lower", "", false, false, false, "")
 	got := string(g.format())
 	if got != test.output {
 		t.Errorf("%s: got\n====\n%s====\nexpected\n====%s", test.name, got, test.output)
 	}
+	if got := string(g.schemas[0].document()); got != statusSchemaDocumentOut {
+		t.Errorf("%s: got\n====\n%s====\nexpected\n====%s", test.name, got, statusSchemaDocumentOut)
+	}
+	if got := string(openAPIComponents(g.schemas)); got != statusOpenAPIOut {
+		t.Errorf("%s: got\n====\n%s====\nexpected\n====%s", test.name, got, statusOpenAPIOut)
+	}
+}
+
+const statusTypeScriptOut = `
+/** Status is the state of a job. */
+export type Status =
+  | "pending"
+  | "running"
+  | "done";
+
+export const StatusValues: readonly Status[] = [
+  "pending",
+  "running",
+  "done",
+];
+
+export enum StatusEnum {
+  /** Pending jobs wait for a worker. */
+  Pending = 0,
+  Running = 1,
+  /** Done jobs have finished. */
+  Done = 2,
+}
+`
+
+func TestGoldenTypeScript(t *testing.T) {
+	test := Golden{"status with TypeScript", statusSchemaIn, statusTypeScriptOut}
+	g := Generator{typeScript: true, tsEnums: true}
+	typeName := loadGolden(t, &g, test)
+	g.generate(typeName, true, false, false, false, "snake", "", false, false, false, "")
+	if got := g.tsBuf.String(); got != test.output {
+		t.Errorf("%s: got\n====\n%s====\nexpected\n====%s", test.name, got, test.output)
+	}
+}
+
+// TestGoldenJSONNamesSchema checks that the JSON Schema and the TypeScript
+// definitions name the values as JSON does.
+func TestGoldenJSONNamesSchema(t *testing.T) {
+	test := Golden{"status with JSON names", statusSchemaIn, ""}
+	g := Generator{jsonSchemas: true, typeScript: true, jsonNames: "upper"}
+	typeName := loadGolden(t, &g, test)
+	g.generate(typeName, true, false, false, false, "snake", "", false, false, false, "")
+	expected := []interface{}{"PENDING", "RUNNING", "DONE"}
+	if got := g.schemas[0].Enum; !reflect.DeepEqual(got, expected) {
+		t.Errorf("%s: got schema enum %q; expected %q", test.name, got, expected)
+	}
+	if got := g.tsBuf.String(); !strings.Contains(got, "export type Status =\n  | \"PENDING\"\n  | \"RUNNING\"\n  | \"DONE\";\n") {
+		t.Errorf("%s: got\n====\n%s====\nexpected the JSON names", test.name, got)
+	}
+}
+
+const dayCHeaderOut = `/* header */
+#ifndef ENUM_H
+#define ENUM_H
+
+enum day {
+	DAY_MONDAY = 0,
+	DAY_TUESDAY = 1,
+	DAY_WEDNESDAY = 2,
+	DAY_THURSDAY = 3,
+	DAY_FRIDAY = 4,
+	DAY_SATURDAY = 5,
+	DAY_SUNDAY = 6,
+};
+
+static const char *day_names[] = {
+	[DAY_MONDAY] = "Monday",
+	[DAY_TUESDAY] = "Tuesday",
+	[DAY_WEDNESDAY] = "Wednesday",
+	[DAY_THURSDAY] = "Thursday",
+	[DAY_FRIDAY] = "Friday",
+	[DAY_SATURDAY] = "Saturday",
+	[DAY_SUNDAY] = "Sunday",
+};
+
+#define DAY_COUNT 7
+
+#endif /* ENUM_H */
+`
+
+const primeCHeaderOut = `/* header */
+#ifndef ENUM_H
+#define ENUM_H
+
+#define PRIME_P2 2
+#define PRIME_P3 3
+#define PRIME_P5 5
+#define PRIME_P7 7
+#define PRIME_P11 11
+#define PRIME_P13 13
+#define PRIME_P17 17
+#define PRIME_P19 19
+#define PRIME_P23 23
+#define PRIME_P29 29
+#define PRIME_P37 31
+#define PRIME_P41 41
+#define PRIME_P43 43
+
+static const char *prime_names[] = {
+	[PRIME_P2] = "p2",
+	[PRIME_P3] = "p3",
+	[PRIME_P5] = "p5",
+	[PRIME_P7] = "p7",
+	[PRIME_P11] = "p11",
+	[PRIME_P13] = "p13",
+	[PRIME_P17] = "p17",
+	[PRIME_P19] = "p19",
+	[PRIME_P23] = "p23",
+	[PRIME_P29] = "p29",
+	[PRIME_P37] = "p37",
+	[PRIME_P41] = "p41",
+	[PRIME_P43] = "p43",
+};
+
+#define PRIME_COUNT 13
+
+#endif /* ENUM_H */
+`
+
+const numCHeaderOut = `/* header */
+#ifndef ENUM_H
+#define ENUM_H
+
+enum num {
+	NUM_M_2 = (-2),
+	NUM_M_1 = (-1),
+	NUM_M0 = 0,
+	NUM_M1 = 1,
+	NUM_M2 = 2,
+};
+
+static const long long num_values[] = {
+	NUM_M_2,
+	NUM_M_1,
+	NUM_M0,
+	NUM_M1,
+	NUM_M2,
+};
+
+static const char *num_names[] = {
+	"m_2",
+	"m_1",
+	"m0",
+	"m1",
+	"m2",
+};
+
+#define NUM_COUNT 5
+
+#endif /* ENUM_H */
+`
+
+const numCDefinesOut = `/* header */
+#ifndef ENUM_H
+#define ENUM_H
+
+#define NUM_M_2 (-2)
+#define NUM_M_1 (-1)
+#define NUM_M0 0
+#define NUM_M1 1
+#define NUM_M2 2
+
+static const long long num_values[] = {
+	NUM_M_2,
+	NUM_M_1,
+	NUM_M0,
+	NUM_M1,
+	NUM_M2,
+};
+
+static const char *num_names[] = {
+	"m_2",
+	"m_1",
+	"m0",
+	"m1",
+	"m2",
+};
+
+#define NUM_COUNT 5
+
+#endif /* ENUM_H */
+`
+
+func TestGoldenCHeader(t *testing.T) {
+	for _, test := range []Golden{
+		{"day with C header", dayIn, dayCHeaderOut},
+		{"prime with C header", primeIn, primeCHeaderOut},
+		{"num with C header", numIn, numCHeaderOut},
+		{"num with C defines", numIn, numCDefinesOut},
+	} {
+		g := Generator{cHeaders: true, cDefines: test.name == "prime with C header" || test.name == "num with C defines"}
+		typeName := loadGolden(t, &g, test)
+		g.generate(typeName, false, false, false, false, "noop", "", false, false, false, "")
+		if got := string(g.cHeader("enum.h", "/* header */")); got != test.output {
+			t.Errorf("%s: got\n====\n%s====\nexpected\n====%s", test.name, got, test.output)
+		}
+	}
+}
+
+const eventDefinedOut = `
+// Event mirrors the EVENT_* constants of event.h.
+type Event int
+
+const (
+	Open Event = 1 // EVENT_OPEN
+	// Close is sent last.
+	Close Event = 2 // EVENT_CLOSE
+)
+
+const _EventName = "openclose"
+
+var _EventMap = map[Event]string{
+	1: _EventName[0:4],
+	2: _EventName[4:9],
+}
+
+func (i Event) String() string {
+	if str, ok := _EventMap[i]; ok {
+		return str
+	}
+	return fmt.Sprintf("Event(%d)", i)
+}
+
+var _EventValues = []Event{1, 2}
+
+var _EventNameToValueMap = map[string]Event{
+	_EventName[0:4]: 1,
+	_EventName[4:9]: 2,
+}
+
+// EventFromString retrieves an enum value from the enum constants string name.
+// Throws an error if the param is not part of the enum.
+func EventFromString(s string) (Event, error) {
+	if val, ok := _EventNameToValueMap[s]; ok {
+		return val, nil
+	}
+	return 0, fmt.Errorf("%s does not belong to Event values", s)
+}
+
+// EventValues returns all values of the enum
+func EventValues() []Event {
+	return _EventValues
+}
+
+// IsAEvent returns "true" if the value is listed in the enum definition. "false" otherwise
+func (i Event) IsAEvent() bool {
+	_, ok := _EventMap[i]
+	return ok
+}
+`
+
+func TestGoldenDefined(t *testing.T) {
+	g := Generator{pkg: &Package{name: "test"}}
+	g.define("Event", &definedType{
+		underlying: "int",
+		doc:        "Event mirrors the EVENT_* constants of event.h.",
+		values: []Value{
+			{constName: "Open", name: "Open", value: 1, signed: true, str: "1", comment: "EVENT_OPEN"},
+			{constName: "Close", name: "Close", value: 2, signed: true, str: "2", comment: "EVENT_CLOSE", doc: "Close is sent last."},
+		},
+	})
+	g.generate("Event", false, false, false, false, "lower", "", false, false, false, "")
+	if got := string(g.format()); got != eventDefinedOut {
+		t.Errorf("defined event: got\n====\n%s====\nexpected\n====%s", got, eventDefinedOut)
+	}
+}
+
+const statusAttrIn = `//enumer:attr severity=info retry_after=0
+type Status int
+
+const (
+	OK       Status = 200 //enumer:attr status=200 label="OK"
+	NotFound Status = 404 //enumer:attr status=404 severity=warn label="Not found"
+	// Teapot is not an error.
+	//enumer:attr status=418 label=Teapot retry_after=1.5
+	Teapot Status = 418
+)
+`
+
+const statusAttrOut = `
+const _StatusName = "OKNotFoundTeapot"
+
+var _StatusMap = map[Status]string{
+	200: _StatusName[0:2],
+	404: _StatusName[2:10],
+	418: _StatusName[10:16],
+}
+
+func (i Status) String() string {
+	if str, ok := _StatusMap[i]; ok {
+		return str
+	}
+	return fmt.Sprintf("Status(%d)", i)
+}
+
+var _StatusValues = []Status{200, 404, 418}
+
+var _StatusNameToValueMap = map[string]Status{
+	_StatusName[0:2]:   200,
+	_StatusName[2:10]:  404,
+	_StatusName[10:16]: 418,
+}
+
+// StatusFromString retrieves an enum value from the enum constants string name.
+// Throws an error if the param is not part of the enum.
+func StatusFromString(s string) (Status, error) {
+	if val, ok := _StatusNameToValueMap[s]; ok {
+		return val, nil
+	}
+	return 0, fmt.Errorf("%s does not belong to Status values", s)
+}
+
+// StatusValues returns all values of the enum
+func StatusValues() []Status {
+	return _StatusValues
+}
+
+// IsAStatus returns "true" if the value is listed in the enum definition. "false" otherwise
+func (i Status) IsAStatus() bool {
+	_, ok := _StatusMap[i]
+	return ok
+}
+
+var _StatusLabelMap = map[Status]string{
+	200: "OK",
+	404: "Not found",
+	418: "Teapot",
+}
+
+// Label returns the label attribute of i, or the zero value if i is not a Status value.
+func (i Status) Label() string {
+	return _StatusLabelMap[i]
+}
+
+var _StatusRetryAfterMap = map[Status]float64{
+	200: 0,
+	404: 0,
+	418: 1.5,
+}
+
+// RetryAfter returns the retry_after attribute of i, or the zero value if i is not a Status value.
+func (i Status) RetryAfter() float64 {
+	return _StatusRetryAfterMap[i]
+}
+
+var _StatusSeverityMap = map[Status]string{
+	200: "info",
+	404: "warn",
+	418: "info",
+}
+
+// Severity returns the severity attribute of i, or the zero value if i is not a Status value.
+func (i Status) Severity() string {
+	return _StatusSeverityMap[i]
+}
+
+var _StatusStatusMap = map[Status]int{
+	200: 200,
+	404: 404,
+	418: 418,
+}
+
+// Status returns the status attribute of i, or the zero value if i is not a Status value.
+func (i Status) Status() int {
+	return _StatusStatusMap[i]
+}
+`
+
+func TestGoldenAttr(t *testing.T) {
+	test := Golden{"status with attributes", statusAttrIn, statusAttrOut}
+	var g Generator
+	typeName := loadGolden(t, &g, test)
+	g.generate(typeName, false, false, false, false, "noop", "", false, false, false, "")
+	if got := string(g.format()); got != test.output {
+		t.Errorf("%s: got\n====\n%s====\nexpected\n====\n%s", test.name, got, test.output)
+	}
+}
+
+const limitAttrIn = `type Limit int
+
+const (
+	Unbounded Limit = iota //enumer:attr max=inf ratio=NaN
+	Bounded                //enumer:attr max="100" ratio=half
+)
+`
+
+const limitAttrOut = `
+var _LimitMaxMap = map[Limit]string{
+	0: "inf",
+	1: "100",
+}
+
+// Max returns the max attribute of i, or the zero value if i is not a Limit value.
+func (i Limit) Max() string {
+	return _LimitMaxMap[i]
+}
+
+var _LimitRatioMap = map[Limit]string{
+	0: "NaN",
+	1: "half",
+}
+
+// Ratio returns the ratio attribute of i, or the zero value if i is not a Limit value.
+func (i Limit) Ratio() string {
+	return _LimitRatioMap[i]
+}
+`
+
+// TestGoldenAttrNotNumbers checks that the words strconv parses as numbers
+// but Go does not, e.g. inf, are string attributes.
+func TestGoldenAttrNotNumbers(t *testing.T) {
+	test := Golden{"limit with inf attributes", limitAttrIn, limitAttrOut}
+	var g Generator
+	typeName := loadGolden(t, &g, test)
+	g.generate(typeName, false, false, false, false, "noop", "", false, false, false, "")
+	if got := string(g.format()); !strings.HasSuffix(got, test.output) {
+		t.Errorf("%s: got\n====\n%s====\nexpected to end with\n====\n%s", test.name, got, test.output)
+	}
+}
+
+const jobDescriptorsIn = `type Job uint8
+
+const (
+	// Pending jobs wait for a worker.
+	Pending Job = iota
+	Running // Not documented: a line comment.
+	// Failed jobs gave up.
+	//
+	// Deprecated: use Done.
+	Failed
+	Done
+	// Queued is an alias for Pending.
+	Queued Job = Pending
+	Waiting Job = Pending
+)
+`
+
+const jobDescriptorsOut = `
+const _JobName = "pendingrunningfaileddone"
+
+var _JobMap = map[Job]string{
+	0: _JobName[0:7],
+	1: _JobName[7:14],
+	2: _JobName[14:20],
+	3: _JobName[20:24],
+}
+
+func (i Job) String() string {
+	if str, ok := _JobMap[i]; ok {
+		return str
+	}
+	return fmt.Sprintf("Job(%d)", i)
+}
+
+var _JobValues = []Job{0, 1, 3}
+
+var _JobNameToValueMap = map[string]Job{
+	_JobName[0:7]:   0,
+	_JobName[7:14]:  1,
+	_JobName[14:20]: 2,
+	_JobName[20:24]: 3,
+}
+
+var _JobDeprecatedMap = map[Job]struct{}{
+	2: {},
+}
+
+// IsDeprecated reports whether the constant declaring i is deprecated.
+func (i Job) IsDeprecated() bool {
+	_, ok := _JobDeprecatedMap[i]
+	return ok
+}
+
+// JobFromString retrieves an enum value from the enum constants string name.
+// Throws an error if the param is not part of the enum.
+func JobFromString(s string) (Job, error) {
+	if val, ok := _JobNameToValueMap[s]; ok {
+		return val, nil
+	}
+	return 0, fmt.Errorf("%s does not belong to Job values", s)
+}
+
+// JobValues returns all values of the enum
+func JobValues() []Job {
+	return _JobValues
+}
+
+// IsAJob returns "true" if the value is listed in the enum definition. "false" otherwise
+func (i Job) IsAJob() bool {
+	_, ok := _JobMap[i]
+	return ok
+}
+
+// JobDescriptor describes a value of Job.
+type JobDescriptor struct {
+	Name        string   // The string representation of the value.
+	Value       Job      // The constant.
+	Description string   // The doc comment of the constant, without the deprecation notice.
+	Deprecated  bool     // Whether the doc comment has a "Deprecated:" paragraph.
+	Aliases     []string // Other constants declared with the same value.
+}
+
+var _JobDescriptors = []JobDescriptor{
+	{Name: "pending", Value: 0, Description: "Pending jobs wait for a worker.", Aliases: []string{"Queued", "Waiting"}},
+	{Name: "running", Value: 1},
+	{Name: "failed", Value: 2, Description: "Failed jobs gave up.", Deprecated: true},
+	{Name: "done", Value: 3},
+}
+
+var _JobDescriptionMap = map[Job]string{
+	0: "Pending jobs wait for a worker.",
+	2: "Failed jobs gave up.",
+}
+
+// JobDescriptors returns a descriptor for each value of the enum
+func JobDescriptors() []JobDescriptor {
+	return _JobDescriptors
+}
+
+// Description returns the doc comment of the constant declaring i, or "" if there is none
+func (i Job) Description() string {
+	return _JobDescriptionMap[i]
+}
+`
+
+func TestGoldenDescriptors(t *testing.T) {
+	test := Golden{"job with descriptors", jobDescriptorsIn, jobDescriptorsOut}
+	g := Generator{descriptors: true}
+	typeName := loadGolden(t, &g, test)
+	g.generate(typeName, false, false, false, false, "snake", "", false, false, false, "")
+	if got := string(g.format()); got != test.output {
+		t.Errorf("%s: got\n====\n%s====\nexpected\n====\n%s", test.name, got, test.output)
+	}
+}
+
+const colorDeprecatedIn = `type Color int
+
+const (
+	Red Color = iota
+	// Deprecated: use Red, which
+	// is brighter.
+	Crimson
+	// Deprecated: renamed to Blue.
+	Azure
+	Blue Color = Azure
+)
+`
+
+const colorDeprecatedOut = `
+const _ColorName = "RedCrimsonAzure"
+
+var _ColorMap = map[Color]string{
+	0: _ColorName[0:3],
+	1: _ColorName[3:10],
+	2: _ColorName[10:15],
+}
+
+func (i Color) String() string {
+	if str, ok := _ColorMap[i]; ok {
+		return str
+	}
+	return fmt.Sprintf("Color(%d)", i)
+}
+
+var _ColorValues = []Color{0, 2}
+
+var _ColorNameToValueMap = map[string]Color{
+	_ColorName[0:3]:   0,
+	_ColorName[3:10]:  1,
+	_ColorName[10:15]: 2,
+}
+
+var _ColorDeprecatedMap = map[Color]struct{}{
+	1: {},
+}
+
+// IsDeprecated reports whether the constant declaring i is deprecated.
+func (i Color) IsDeprecated() bool {
+	_, ok := _ColorDeprecatedMap[i]
+	return ok
+}
+
+// ColorDeprecatedHook, if not nil, is called with each deprecated value decoded from a string.
+var ColorDeprecatedHook func(Color)
+
+// ColorFromString retrieves an enum value from the enum constants string name.
+// Throws an error if the param is not part of the enum.
+func ColorFromString(s string) (Color, error) {
+	if val, ok := _ColorNameToValueMap[s]; ok {
+		if _, ok := _ColorDeprecatedMap[val]; ok && ColorDeprecatedHook != nil {
+			ColorDeprecatedHook(val)
+		}
+		return val, nil
+	}
+	i, err := strconv.Atoi(s)
+	if err == nil {
+		for _, v := range _ColorNameToValueMap {
+			if int(v) == i {
+				if _, ok := _ColorDeprecatedMap[v]; ok && ColorDeprecatedHook != nil {
+					ColorDeprecatedHook(v)
+				}
+				return v, nil
+			}
+		}
+	}
+	return 0, fmt.Errorf("%s does not belong to Color values", s)
+}
+
+// ColorValues returns all values of the enum
+func ColorValues() []Color {
+	return _ColorValues
+}
+
+// IsAColor returns "true" if the value is listed in the enum definition. "false" otherwise
+func (i Color) IsAColor() bool {
+	_, ok := _ColorMap[i]
+	return ok
+}
+`
+
+const colorDeprecatedGraphQLOut = `
+enum Color {
+  Red
+  Crimson @deprecated(reason: "use Red, which is brighter.")
+  Azure
+}
+`
+
+const colorDeprecatedTypeScriptOut = `
+export type Color =
+  | "Red"
+  | "Crimson"
+  | "Azure";
+
+export const ColorValues: readonly Color[] = [
+  "Red",
+  "Azure",
+];
+`
+
+func TestGoldenDeprecated(t *testing.T) {
+	test := Golden{"color with deprecated values", colorDeprecatedIn, colorDeprecatedOut}
+	g := Generator{deprecatedHook: true, graphqlEnums: true, typeScript: true}
+	typeName := loadGolden(t, &g, test)
+	g.generate(typeName, false, false, false, false, "noop", "", false, false, true, "")
+	if got := string(g.format()); got != test.output {
+		t.Errorf("%s: got\n====\n%s====\nexpected\n====\n%s", test.name, got, test.output)
+	}
+	if got := g.graphqlBuf.String(); got != colorDeprecatedGraphQLOut {
+		t.Errorf("%s: got\n====\n%s====\nexpected\n====\n%s", test.name, got, colorDeprecatedGraphQLOut)
+	}
+	if got := g.tsBuf.String(); got != colorDeprecatedTypeScriptOut {
+		t.Errorf("%s: got\n====\n%s====\nexpected\n====\n%s", test.name, got, colorDeprecatedTypeScriptOut)
+	}
+}
+
+const dayRegistryOut = `
+func init() {
+	registry.Register("example.com/test.Day", DayValues, DayFromString, Day.IsADay)
+}
+`
+
+func TestGoldenRegistry(t *testing.T) {
+	test := Golden{"day with registry", dayIn, dayRegistryOut}
+	g := Generator{registry: true}
+	typeName := loadGolden(t, &g, test)
+	g.pkg.path = "example.com/test"
+	g.generate(typeName, false, false, false, false, "noop", "", false, false, false, "")
+	if got := string(g.format()); !strings.HasSuffix(got, test.output) {
+		t.Errorf("%s: got\n====\n%s====\nexpected to end with\n====\n%s", test.name, got, test.output)
+	}
+}
+
+const dayGenericOut = `
+var _DayEnumDescriptor = enumer.Descriptor[Day]{
+	Name:    "Day",
+	Values:  DayValues,
+	Parse:   DayFromString,
+	IsValid: Day.IsADay,
+}
+
+// EnumDescriptor returns the functions of Day used by the generic helpers of the enumer package
+func (Day) EnumDescriptor() enumer.Descriptor[Day] {
+	return _DayEnumDescriptor
+}
+`
+
+func TestGoldenGeneric(t *testing.T) {
+	test := Golden{"day with generic", dayIn, dayGenericOut}
+	g := Generator{generic: true}
+	typeName := loadGolden(t, &g, test)
+	g.generate(typeName, false, false, false, false, "noop", "", false, false, false, "")
+	if got := string(g.format()); !strings.HasSuffix(got, test.output) {
+		t.Errorf("%s: got\n====\n%s====\nexpected to end with\n====\n%s", test.name, got, test.output)
+	}
+}
+
+const dayFromIntOut = `
+// DayFromInt returns the value of Day equal to i.
+// Throws an error if there is none, unlike a conversion.
+func DayFromInt(i int) (Day, error) {
+	if v := Day(i); int(v) == i && v.IsADay() {
+		return v, nil
+	}
+	return 0, fmt.Errorf("%d does not belong to Day values", i)
+}
+`
+
+func TestGoldenFromInt(t *testing.T) {
+	test := Golden{"day with FromInt", dayIn, dayFromIntOut}
+	g := Generator{fromInt: true}
+	typeName := loadGolden(t, &g, test)
+	g.generate(typeName, false, false, false, false, "noop", "", false, false, false, "")
+	if got := string(g.format()); !strings.HasSuffix(got, test.output) {
+		t.Errorf("%s: got\n====\n%s====\nexpected to end with\n====\n%s", test.name, got, test.output)
+	}
+}
+
+const planReservedIn = `// Plan is a subscription plan.
+//
+//enumer:reserved 2, 4-6, "Legacy"
+type Plan int
+
+const (
+	Free Plan = iota
+	Basic
+	Pro Plan = 3
+	Team Plan = 7
+)
+`
+
+const planReservedOut = `
+const _PlanName = "FreeBasicProTeam"
+
+var _PlanMap = map[Plan]string{
+	0: _PlanName[0:4],
+	1: _PlanName[4:9],
+	3: _PlanName[9:12],
+	7: _PlanName[12:16],
+}
+
+func (i Plan) String() string {
+	if str, ok := _PlanMap[i]; ok {
+		return str
+	}
+	return fmt.Sprintf("Plan(%d)", i)
+}
+
+var _PlanValues = []Plan{0, 1, 3, 7}
+
+var _PlanNameToValueMap = map[string]Plan{
+	_PlanName[0:4]:   0,
+	_PlanName[4:9]:   1,
+	_PlanName[9:12]:  3,
+	_PlanName[12:16]: 7,
+}
+
+// ErrPlanReserved is wrapped by the errors returned when decoding a reserved number or name of Plan.
+var ErrPlanReserved = errors.New("reserved Plan value")
+
+var _PlanReservedNames = map[string]struct{}{
+	"Legacy": {},
+}
+
+func _PlanIsReserved(i int64) bool {
+	return i == 2 || 4 <= i && i <= 6
+}
+
+// PlanFromString retrieves an enum value from the enum constants string name.
+// Throws an error if the param is not part of the enum.
+func PlanFromString(s string) (Plan, error) {
+	if val, ok := _PlanNameToValueMap[s]; ok {
+		return val, nil
+	}
+	i, err := strconv.Atoi(s)
+	if err == nil {
+		for _, v := range _PlanNameToValueMap {
+			if int(v) == i {
+				return v, nil
+			}
+		}
+	}
+	if _, ok := _PlanReservedNames[s]; ok {
+		return 0, fmt.Errorf("%s: %w", s, ErrPlanReserved)
+	}
+	if i, err := strconv.Atoi(s); err == nil && _PlanIsReserved(int64(i)) {
+		return 0, fmt.Errorf("%s: %w", s, ErrPlanReserved)
+	}
+	return 0, fmt.Errorf("%s does not belong to Plan values", s)
+}
+
+// PlanValues returns all values of the enum
+func PlanValues() []Plan {
+	return _PlanValues
+}
+
+// IsAPlan returns "true" if the value is listed in the enum definition. "false" otherwise
+func (i Plan) IsAPlan() bool {
+	_, ok := _PlanMap[i]
+	return ok
+}
+
+// PlanFromInt returns the value of Plan equal to i.
+// Throws an error if there is none, unlike a conversion.
+func PlanFromInt(i int) (Plan, error) {
+	if v := Plan(i); int(v) == i && v.IsAPlan() {
+		return v, nil
+	}
+	if _PlanIsReserved(int64(i)) {
+		return 0, fmt.Errorf("%d: %w", i, ErrPlanReserved)
+	}
+	return 0, fmt.Errorf("%d does not belong to Plan values", i)
+}
+`
+
+func TestGoldenReserved(t *testing.T) {
+	test := Golden{"plan with reserved values", planReservedIn, planReservedOut}
+	g := Generator{fromInt: true}
+	typeName := loadGolden(t, &g, test)
+	g.generate(typeName, false, false, false, false, "noop", "", false, false, true, "")
+	if got := string(g.format()); got != test.output {
+		t.Errorf("%s: got\n====\n%s====\nexpected\n====\n%s", test.name, got, test.output)
+	}
+}
+
+const planReservedPrefixedIn = `// Plan is a subscription plan.
+//
+//enumer:reserved "PlanLegacyTier"
+type Plan int
+
+const (
+	PlanFree Plan = iota
+	PlanBasic
+)
+`
+
+// TestGoldenReservedTransform checks that the reserved names are trimmed and
+// transformed as the names of the values.
+func TestGoldenReservedTransform(t *testing.T) {
+	g := Generator{}
+	typeName := loadGolden(t, &g, Golden{"plan with prefixed reserved name", planReservedPrefixedIn, ""})
+	g.generate(typeName, false, false, false, false, "snake", "Plan", false, false, false, "")
+	expected := "var _PlanReservedNames = map[string]struct{}{\n\t\"legacy_tier\": {},\n}\n"
+	if got := string(g.format()); !strings.Contains(got, expected) {
+		t.Errorf("got\n====\n%s====\nexpected to contain\n====\n%s", got, expected)
+	}
+}
+
+const apiTransformIn = `type API int
+
+const (
+	GraphQLQuery API = iota
+	HTTPStatusOK
+	UserIDs
+	Day2Night
+)
+`
+
+// The declaration of the names of apiTransformIn by transform, with the
+// acronyms GraphQL, HTTP and ID.
+var apiTransformNames = []struct {
+	transform string
+	names     string
+}{
+	{"snake", `const _APIName = "graphql_queryhttp_status_okuser_idsday2_night"`},
+	{"kebabu", `const _APIName = "GRAPHQL-QUERYHTTP-STATUS-OKUSER-IDSDAY2-NIGHT"`},
+	{"camel", `const _APIName = "graphqlQueryhttpStatusOKuserIDsday2Night"`},
+	{"pascal", `const _APIName = "GraphQLQueryHTTPStatusOKUserIDsDay2Night"`},
+	{"title", `const _APIName = "GraphQL QueryHTTP Status OKUser IDsDay2 Night"`},
+	{"dot", `const _APIName = "graphql.queryhttp.status.okuser.idsday2.night"`},
+	{"space", `const _APIName = "graphql queryhttp status okuser idsday2 night"`},
+	{"train", `const _APIName = "GraphQL-QueryHTTP-Status-OKUser-IDsDay2-Night"`},
+}
+
+func TestGoldenTransforms(t *testing.T) {
+	for _, test := range apiTransformNames {
+		g := Generator{acronyms: []string{"HTTP", "GraphQL", "ID"}}
+		typeName := loadGolden(t, &g, Golden{"api " + test.transform, apiTransformIn, ""})
+		g.generate(typeName, false, false, false, false, test.transform, "", false, false, false, "")
+		if got := string(g.format()); !strings.Contains(got, "\n"+test.names+"\n") {
+			t.Errorf("%s: got\n====\n%s====\nexpected to contain\n====\n%s\n", test.transform, got, test.names)
+		}
+	}
+}
+
+const metricRulesIn = `type Metric int
+
+const (
+	CPUPctMetric Metric = iota
+	DiskReadBytesMetric
+	ErrorRatePctMetric
+)
+`
+
+func TestGoldenTransformRules(t *testing.T) {
+	var g Generator
+	for _, rule := range []string{`{{ . | trimSuffix "Metric" }}`, "s/Pct$/Percent/", "{{ snake . }}", "s|_|.|"} {
+		r, err := parseNameRule(rule, []string{"CPU"})
+		if err != nil {
+			t.Fatal(err)
+		}
+		g.nameRules = append(g.nameRules, r)
+	}
+	typeName := loadGolden(t, &g, Golden{"metric with rules", metricRulesIn, ""})
+	g.generate(typeName, false, false, false, false, "noop", "", false, false, false, "")
+	names := `const _MetricName = "cpu.percentdisk.read.byteserror.rate.percent"`
+	if got := string(g.format()); !strings.Contains(got, "\n"+names+"\n") {
+		t.Errorf("got\n====\n%s====\nexpected to contain\n====\n%s\n", got, names)
+	}
+}
+
+const dayNameSetsIn = `type Day int
+
+const (
+	Monday  Day = iota //enumer:attr db=MON
+	Tuesday            //enumer:attr db=TUE
+)
+`
+
+const dayNameSetsOut = `
+const _DayName = "MondayTuesday"
+
+var _DayMap = map[Day]string{
+	0: _DayName[0:6],
+	1: _DayName[6:13],
+}
+
+func (i Day) String() string {
+	if str, ok := _DayMap[i]; ok {
+		return str
+	}
+	return fmt.Sprintf("Day(%d)", i)
+}
+
+var _DayValues = []Day{0, 1}
+
+var _DayNameToValueMap = map[string]Day{
+	_DayName[0:6]:  0,
+	_DayName[6:13]: 1,
+}
+
+// DayFromString retrieves an enum value from the enum constants string name.
+// Throws an error if the param is not part of the enum.
+func DayFromString(s string) (Day, error) {
+	if val, ok := _DayNameToValueMap[s]; ok {
+		return val, nil
+	}
+	if val, ok := _DayJSONNameToValueMap[s]; ok {
+		return val, nil
+	}
+	if val, ok := _DaySQLNameToValueMap[s]; ok {
+		return val, nil
+	}
+	return 0, fmt.Errorf("%s does not belong to Day values", s)
+}
+
+// DayValues returns all values of the enum
+func DayValues() []Day {
+	return _DayValues
+}
+
+// IsADay returns "true" if the value is listed in the enum definition. "false" otherwise
+func (i Day) IsADay() bool {
+	_, ok := _DayMap[i]
+	return ok
+}
+
+var _DayDbMap = map[Day]string{
+	0: "MON",
+	1: "TUE",
+}
+
+// Db returns the db attribute of i, or the zero value if i is not a Day value.
+func (i Day) Db() string {
+	return _DayDbMap[i]
+}
+
+var _DayJSONNames = map[Day]string{
+	0: "monday",
+	1: "tuesday",
+}
+
+var _DayJSONNameToValueMap = map[string]Day{
+	"monday":  0,
+	"tuesday": 1,
+}
+
+// JSONName returns the JSON name of i.
+func (i Day) JSONName() string {
+	if s, ok := _DayJSONNames[i]; ok {
+		return s
+	}
+	return fmt.Sprintf("Day(%d)", i)
+}
+
+// DayFromJSONName retrieves an enum value from its JSON name.
+// Throws an error if the param is not a JSON name of the enum.
+func DayFromJSONName(s string) (Day, error) {
+	if val, ok := _DayJSONNameToValueMap[s]; ok {
+		return val, nil
+	}
+	return DayFromString(s)
+}
+
+var _DaySQLNames = map[Day]string{
+	0: "MON",
+	1: "TUE",
+}
+
+var _DaySQLNameToValueMap = map[string]Day{
+	"MON": 0,
+	"TUE": 1,
+}
+
+// SQLName returns the SQL name of i.
+func (i Day) SQLName() string {
+	if s, ok := _DaySQLNames[i]; ok {
+		return s
+	}
+	return fmt.Sprintf("Day(%d)", i)
+}
+
+// DayFromSQLName retrieves an enum value from its SQL name.
+// Throws an error if the param is not a SQL name of the enum.
+func DayFromSQLName(s string) (Day, error) {
+	if val, ok := _DaySQLNameToValueMap[s]; ok {
+		return val, nil
+	}
+	return DayFromString(s)
+}
+
+// MarshalJSON implements the json.Marshaler interface for Day
+func (i Day) MarshalJSON() ([]byte, error) {
+	return json.Marshal(i.JSONName())
+}
+
+// UnmarshalJSON implements the json.Unmarshaler interface for Day
+func (i *Day) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return fmt.Errorf("Day should be a string, got %s", data)
+	}
+
+	var err error
+	*i, err = DayFromJSONName(s)
+	return err
+}
+
+func (i Day) Value() (driver.Value, error) {
+	return i.SQLName(), nil
+}
+
+func (i *Day) Scan(value interface{}) error {
+	if value == nil {
+		return nil
+	}
+
+	str, ok := value.(string)
+	if !ok {
+		bytes, ok := value.([]byte)
+		if !ok {
+			return fmt.Errorf("value is not a byte slice")
+		}
+
+		str = string(bytes[:])
+	}
+
+	val, err := DayFromSQLName(str)
+	if err != nil {
+		return err
+	}
+
+	*i = val
+	return nil
+}
+`
+
+const planNameSetsIn = `// Plan is a subscription plan.
+//
+//enumer:reserved 3, "LegacyTier"
+type Plan int
+
+const (
+	Free Plan = iota
+	// Deprecated: use Free.
+	BasicTier
+	ProTier
+)
+`
+
+const planNameSetsOut = `
+const _PlanName = "FreeBasicTierProTier"
+
+var _PlanMap = map[Plan]string{
+	0: _PlanName[0:4],
+	1: _PlanName[4:13],
+	2: _PlanName[13:20],
+}
+
+func (i Plan) String() string {
+	if str, ok := _PlanMap[i]; ok {
+		return str
+	}
+	return fmt.Sprintf("Plan(%d)", i)
+}
+
+var _PlanValues = []Plan{0, 2}
+
+var _PlanNameToValueMap = map[string]Plan{
+	_PlanName[0:4]:   0,
+	_PlanName[4:13]:  1,
+	_PlanName[13:20]: 2,
+}
+
+var _PlanDeprecatedMap = map[Plan]struct{}{
+	1: {},
+}
+
+// IsDeprecated reports whether the constant declaring i is deprecated.
+func (i Plan) IsDeprecated() bool {
+	_, ok := _PlanDeprecatedMap[i]
+	return ok
+}
+
+// PlanDeprecatedHook, if not nil, is called with each deprecated value decoded from a string.
+var PlanDeprecatedHook func(Plan)
+
+// ErrPlanReserved is wrapped by the errors returned when decoding a reserved number or name of Plan.
+var ErrPlanReserved = errors.New("reserved Plan value")
+
+var _PlanReservedNames = map[string]struct{}{
+	"LegacyTier":  {},
+	"legacy_tier": {},
+	"LEGACYTIER":  {},
+}
+
+func _PlanIsReserved(i int64) bool {
+	return i == 3
+}
+
+// PlanFromString retrieves an enum value from the enum constants string name.
+// Throws an error if the param is not part of the enum.
+func PlanFromString(s string) (Plan, error) {
+	if val, ok := _PlanNameToValueMap[s]; ok {
+		if _, ok := _PlanDeprecatedMap[val]; ok && PlanDeprecatedHook != nil {
+			PlanDeprecatedHook(val)
+		}
+		return val, nil
+	}
+	i, err := strconv.Atoi(s)
+	if err == nil {
+		for _, v := range _PlanNameToValueMap {
+			if int(v) == i {
+				if _, ok := _PlanDeprecatedMap[v]; ok && PlanDeprecatedHook != nil {
+					PlanDeprecatedHook(v)
+				}
+				return v, nil
+			}
+		}
+	}
+	if _, ok := _PlanReservedNames[s]; ok {
+		return 0, fmt.Errorf("%s: %w", s, ErrPlanReserved)
+	}
+	if i, err := strconv.Atoi(s); err == nil && _PlanIsReserved(int64(i)) {
+		return 0, fmt.Errorf("%s: %w", s, ErrPlanReserved)
+	}
+	return 0, fmt.Errorf("%s does not belong to Plan values", s)
+}
+
+// PlanValues returns all values of the enum
+func PlanValues() []Plan {
+	return _PlanValues
+}
+
+// IsAPlan returns "true" if the value is listed in the enum definition. "false" otherwise
+func (i Plan) IsAPlan() bool {
+	_, ok := _PlanMap[i]
+	return ok
+}
+
+var _PlanJSONNames = map[Plan]string{
+	0: "free",
+	1: "basic_tier",
+	2: "pro_tier",
+}
+
+var _PlanJSONNameToValueMap = map[string]Plan{
+	"free":       0,
+	"basic_tier": 1,
+	"pro_tier":   2,
+}
+
+// JSONName returns the JSON name of i.
+func (i Plan) JSONName() string {
+	if s, ok := _PlanJSONNames[i]; ok {
+		return s
+	}
+	return fmt.Sprintf("Plan(%d)", i)
+}
+
+// PlanFromJSONName retrieves an enum value from its JSON name.
+// Throws an error if the param is not a JSON name of the enum.
+func PlanFromJSONName(s string) (Plan, error) {
+	if val, ok := _PlanJSONNameToValueMap[s]; ok {
+		if _, ok := _PlanDeprecatedMap[val]; ok && PlanDeprecatedHook != nil {
+			PlanDeprecatedHook(val)
+		}
+		return val, nil
+	}
+	i, err := strconv.Atoi(s)
+	if err == nil {
+		for _, v := range _PlanNameToValueMap {
+			if int(v) == i {
+				if _, ok := _PlanDeprecatedMap[v]; ok && PlanDeprecatedHook != nil {
+					PlanDeprecatedHook(v)
+				}
+				return v, nil
+			}
+		}
+	}
+	if _, ok := _PlanReservedNames[s]; ok {
+		return 0, fmt.Errorf("%s: %w", s, ErrPlanReserved)
+	}
+	if i, err := strconv.Atoi(s); err == nil && _PlanIsReserved(int64(i)) {
+		return 0, fmt.Errorf("%s: %w", s, ErrPlanReserved)
+	}
+	return 0, fmt.Errorf("%s does not belong to Plan JSON names", s)
+}
+
+var _PlanSQLNames = map[Plan]string{
+	0: "FREE",
+	1: "BASICTIER",
+	2: "PROTIER",
+}
+
+var _PlanSQLNameToValueMap = map[string]Plan{
+	"FREE":      0,
+	"BASICTIER": 1,
+	"PROTIER":   2,
+}
+
+// SQLName returns the SQL name of i.
+func (i Plan) SQLName() string {
+	if s, ok := _PlanSQLNames[i]; ok {
+		return s
+	}
+	return fmt.Sprintf("Plan(%d)", i)
+}
+
+// PlanFromSQLName retrieves an enum value from its SQL name.
+// Throws an error if the param is not a SQL name of the enum.
+func PlanFromSQLName(s string) (Plan, error) {
+	if val, ok := _PlanSQLNameToValueMap[s]; ok {
+		if _, ok := _PlanDeprecatedMap[val]; ok && PlanDeprecatedHook != nil {
+			PlanDeprecatedHook(val)
+		}
+		return val, nil
+	}
+	i, err := strconv.Atoi(s)
+	if err == nil {
+		for _, v := range _PlanNameToValueMap {
+			if int(v) == i {
+				if _, ok := _PlanDeprecatedMap[v]; ok && PlanDeprecatedHook != nil {
+					PlanDeprecatedHook(v)
+				}
+				return v, nil
+			}
+		}
+	}
+	if _, ok := _PlanReservedNames[s]; ok {
+		return 0, fmt.Errorf("%s: %w", s, ErrPlanReserved)
+	}
+	if i, err := strconv.Atoi(s); err == nil && _PlanIsReserved(int64(i)) {
+		return 0, fmt.Errorf("%s: %w", s, ErrPlanReserved)
+	}
+	return 0, fmt.Errorf("%s does not belong to Plan SQL names", s)
+}
+
+// MarshalJSON implements the json.Marshaler interface for Plan
+func (i Plan) MarshalJSON() ([]byte, error) {
+	return json.Marshal(i.JSONName())
+}
+
+// UnmarshalJSON implements the json.Unmarshaler interface for Plan
+func (i *Plan) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return fmt.Errorf("Plan should be a string, got %s", data)
+	}
+
+	var err error
+	*i, err = PlanFromJSONName(s)
+	return err
+}
+
+func (i Plan) Value() (driver.Value, error) {
+	return i.SQLName(), nil
+}
+
+func (i *Plan) Scan(value interface{}) error {
+	if value == nil {
+		return nil
+	}
+
+	str, ok := value.(string)
+	if !ok {
+		bytes, ok := value.([]byte)
+		if !ok {
+			return fmt.Errorf("value is not a byte slice")
+		}
+
+		str = string(bytes[:])
+	}
+
+	val, err := PlanFromSQLName(str)
+	if err != nil {
+		return err
+	}
+
+	*i = val
+	return nil
+}
+`
+
+func TestGoldenNameSets(t *testing.T) {
+	test := Golden{"day with name sets", dayNameSetsIn, dayNameSetsOut}
+	g := Generator{jsonNames: "lower", sqlNames: "attr:db", decodeAny: true}
+	typeName := loadGolden(t, &g, test)
+	g.generate(typeName, true, false, true, false, "noop", "", false, false, false, "")
+	if got := string(g.format()); got != test.output {
+		t.Errorf("%s: got\n====\n%s====\nexpected\n====\n%s", test.name, got, test.output)
+	}
+}
+
+// TestGoldenNameSetsChecks checks that the names of the sets decode with the
+// checks of FromString: the deprecated hook, numbers and reserved values.
+func TestGoldenNameSetsChecks(t *testing.T) {
+	test := Golden{"plan with name sets", planNameSetsIn, planNameSetsOut}
+	g := Generator{jsonNames: "snake", sqlNames: "upper", deprecatedHook: true}
+	typeName := loadGolden(t, &g, test)
+	g.generate(typeName, true, false, true, false, "noop", "", false, false, true, "")
+	if got := string(g.format()); got != test.output {
+		t.Errorf("%s: got\n====\n%s====\nexpected\n====\n%s", test.name, got, test.output)
+	}
+}
+
+// TestGoldenNameSetsIgnoreCase checks that the names of the sets decode
+// ignoring case, with the hook, under -ignorecase and -decodeany.
+func TestGoldenNameSetsIgnoreCase(t *testing.T) {
+	g := Generator{jsonNames: "snake", decodeAny: true, deprecatedHook: true}
+	typeName := loadGolden(t, &g, Golden{"plan with name sets ignoring case", planNameSetsIn, ""})
+	g.generate(typeName, true, false, false, false, "noop", "", false, true, false, "")
+	got := string(g.format())
+	for _, expected := range []string{
+		"\tif val, ok := _PlanJSONNameToValueMap[strings.ToLower(s)]; ok {\n\t\tif _, ok := _PlanDeprecatedMap[val]; ok && PlanDeprecatedHook != nil {",
+		"\t\treturn val, nil\n\t}\n\treturn PlanFromString(s)\n}\n",
+		"\t\"legacytier\":  {},\n\t\"legacy_tier\": {},\n",
+	} {
+		if !strings.Contains(got, expected) {
+			t.Errorf("got\n====\n%s====\nexpected to contain\n====\n%s", got, expected)
+		}
+	}
 }
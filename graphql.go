@@ -0,0 +1,70 @@
+package main
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// graphqlName matches the names GraphQL allows for enum values.
+var graphqlName = regexp.MustCompile(`^[_A-Za-z][_0-9A-Za-z]*$`)
+
+// checkGraphQLNames fails if a value name cannot be used as a GraphQL enum
+// value.
+func checkGraphQLNames(runs [][]Value, typeName string) {
+	for _, values := range runs {
+		for _, value := range values {
+			switch {
+			case !graphqlName.MatchString(value.name):
+				fatalf("%s: name %q of %s is not a valid GraphQL enum value", typeName, value.name, value.constName)
+			case value.name == "true" || value.name == "false" || value.name == "null":
+				fatalf("%s: name %q of %s is reserved in GraphQL", typeName, value.name, value.constName)
+			}
+		}
+	}
+}
+
+// Arguments to format are:
+//	[1]: type name
+const gqlMethods = `
+// MarshalGQL implements the graphql.Marshaler interface for %[1]s
+func (i %[1]s) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(i.String()))
+}
+
+// UnmarshalGQL implements the graphql.Unmarshaler interface for %[1]s
+func (i *%[1]s) UnmarshalGQL(value interface{}) error {
+	str, ok := value.(string)
+	if !ok {
+		return fmt.Errorf("%[1]s should be a string, got %%T", value)
+	}
+
+	var err error
+	*i, err = %[1]sFromString(str)
+	return err
+}
+`
+
+func (g *Generator) buildGQLMethods(runs [][]Value, typeName string) {
+	checkGraphQLNames(runs, typeName)
+	g.Printf(gqlMethods, typeName)
+}
+
+// buildGraphQLEnum writes the GraphQL schema definition for the type.
+func (g *Generator) buildGraphQLEnum(runs [][]Value, typeName string) {
+	checkGraphQLNames(runs, typeName)
+	b := &g.graphqlBuf
+	fmt.Fprintf(b, "\nenum %s {\n", typeName)
+	for _, values := range runs {
+		for _, value := range values {
+			fmt.Fprintf(b, "  %s", value.name)
+			if _, notice := deprecationNotice(value.doc); value.deprecated() {
+				reason := strings.TrimSpace(strings.TrimPrefix(notice, "Deprecated:"))
+				fmt.Fprintf(b, " @deprecated(reason: %s)", strconv.Quote(strings.Join(strings.Fields(reason), " ")))
+			}
+			fmt.Fprintf(b, "\n")
+		}
+	}
+	fmt.Fprintf(b, "}\n")
+}
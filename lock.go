@@ -0,0 +1,114 @@
+package main
+
+import (
+	"bytes"
+	jsonenc "encoding/json"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// lockFileName is the file, next to the source of the types, recording the
+// values of their constants.
+const lockFileName = "enumer.lock"
+
+// lockedValues maps the types to the values of their constants, by constant
+// name. Inserting a constant in an iota block renumbers the constants after
+// it, which corrupts the integers stored by earlier versions of a program;
+// checking the values against the lock file catches it.
+type lockedValues map[string]map[string]jsonenc.Number
+
+// lockValues records the values of the constants of the type, aliases
+// included.
+func (g *Generator) lockValues(typeName string, values []Value) {
+	current := make(map[string]jsonenc.Number, len(values))
+	for _, v := range values {
+		current[v.constName] = jsonenc.Number(v.str)
+	}
+	g.lock[typeName] = current
+}
+
+// checkLock returns the changes from locked to current that would make the
+// stored integers mean something else: constants with a new value, and new
+// constants taking a value that no constant keeps.
+func checkLock(typeName string, locked, current map[string]jsonenc.Number) []string {
+	var problems []string
+	owners := make(map[jsonenc.Number][]string)
+	for name, v := range locked {
+		owners[v] = append(owners[v], name)
+	}
+	for name, v := range current {
+		if old, ok := locked[name]; ok {
+			if old != v {
+				problems = append(problems, fmt.Sprintf("%s.%s changed from %s to %s", typeName, name, old, v))
+			}
+			continue
+		}
+		// The value still means the same thing if one of its owners is left.
+		var removed []string
+		for _, owner := range owners[v] {
+			if current[owner] == v {
+				removed = nil
+				break
+			}
+			removed = append(removed, owner)
+		}
+		if len(removed) > 0 {
+			sort.Strings(removed)
+			problems = append(problems, fmt.Sprintf("%s.%s takes the value %s of %s", typeName, name, v, strings.Join(removed, ", ")))
+		}
+	}
+	sort.Strings(problems)
+	return problems
+}
+
+// readLock reads the lock file, which may not exist yet.
+func readLock(fileName string) lockedValues {
+	locked := make(lockedValues)
+	data, err := ioutil.ReadFile(fileName)
+	if os.IsNotExist(err) {
+		return locked
+	}
+	if err != nil {
+		log.Fatal(err)
+	}
+	dec := jsonenc.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	if err := dec.Decode(&locked); err != nil {
+		log.Fatalf("%s: %s", fileName, err)
+	}
+	return locked
+}
+
+// updateLockFile checks the values of the generated types against the lock
+// file in dir and adds the new constants to it. Removed constants stay in the
+// file, so that their values are not reused. If update is true, the values of
+// the types replace the locked ones instead.
+func (g *Generator) updateLockFile(dir string, update bool) {
+	fileName := filepath.Join(dir, lockFileName)
+	locked := readLock(fileName)
+	var problems []string
+	for typeName, current := range g.lock {
+		if update || locked[typeName] == nil {
+			locked[typeName] = current
+			continue
+		}
+		problems = append(problems, checkLock(typeName, locked[typeName], current)...)
+		for name, v := range current {
+			locked[typeName][name] = v
+		}
+	}
+	if len(problems) > 0 {
+		sort.Strings(problems)
+		log.Fatalf("%s: %s; run with -update-lock to accept the changes", fileName, strings.Join(problems, "; "))
+	}
+	data, err := jsonenc.MarshalIndent(locked, "", "\t")
+	if err != nil {
+		log.Fatal(err)
+	}
+	writeOutput(fileName, fileName, append(data, '\n'))
+}
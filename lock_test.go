@@ -0,0 +1,27 @@
+// This file contains tests for the checks against enumer.lock.
+
+package main
+
+import (
+	jsonenc "encoding/json"
+	"reflect"
+	"testing"
+)
+
+func TestCheckLock(t *testing.T) {
+	locked := map[string]jsonenc.Number{"Monday": "0", "Mon": "0", "Tuesday": "1", "Wednesday": "2", "Friday": "4"}
+	current := map[string]jsonenc.Number{
+		"Monday":   "0", // Unchanged.
+		"Lundi":    "0", // New alias of a constant still declared.
+		"Tuesday":  "3", // Renumbered.
+		"Thursday": "2", // Takes the value of the removed Wednesday.
+		"Saturday": "5", // New value.
+	}
+	expected := []string{
+		"Day.Thursday takes the value 2 of Wednesday",
+		"Day.Tuesday changed from 1 to 3",
+	}
+	if got := checkLock("Day", locked, current); !reflect.DeepEqual(got, expected) {
+		t.Errorf("got %q; expected %q", got, expected)
+	}
+}
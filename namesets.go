@@ -0,0 +1,203 @@
+package main
+
+import (
+	"fmt"
+	"go/token"
+	"strconv"
+	"strings"
+)
+
+// nameSet is a set of names of the values for a format other than String,
+// e.g. "monday" for JSON while String returns "Monday".
+type nameSet struct {
+	format string            // JSON or SQL, used in the names of the generated code.
+	spec   string            // How the set names the values, as for newNameSet.
+	names  map[uint64]string // By value.
+}
+
+// nameSetTransforms holds the transform methods a name set may use.
+var nameSetTransforms = map[string]bool{
+	"noop": true, "lower": true, "upper": true, "json": true, "snake": true, "snakeu": true, "kebab": true, "kebabu": true,
+	"dot": true, "space": true, "camel": true, "pascal": true, "title": true, "train": true,
+}
+
+// checkNameSetSpec fails unless the spec is one newNameSet understands, or
+// empty for the names of String.
+func checkNameSetSpec(spec string) error {
+	switch {
+	case spec == "", spec == "linecomment", nameSetTransforms[spec]:
+		return nil
+	case strings.HasPrefix(spec, "attr:"):
+		if key := strings.TrimPrefix(spec, "attr:"); !token.IsIdentifier(key) {
+			return fmt.Errorf("invalid attribute name %q", key)
+		}
+		return nil
+	}
+	return fmt.Errorf("unknown name set %q: want a transform method, linecomment or attr:<key>", spec)
+}
+
+// newNameSet names the values for the format as the spec says: a transform
+// method, "linecomment" for the line comments, or "attr:key" for the string
+// attribute key of the enumer:attr comments. The values are named after the
+// constants, the prefix trimmed, when the spec gives them no name. It fails if
+// values of the type share a name, as the decoding with the case match looks
+// it up.
+func (g *Generator) newNameSet(typeName, format, spec string, values []Value, trimPrefix string, match CaseMatch) *nameSet {
+	named := append([]Value(nil), values...)
+	g.trimValueNames(named, trimPrefix)
+	g.nameBySpec(named, format, spec)
+	if err := checkNameCollisions(named, match); err != nil {
+		fatalf("%s names of %s collide:%s", format, typeName, err)
+	}
+	set := &nameSet{format: format, spec: spec, names: make(map[uint64]string)}
+	for _, v := range named {
+		if _, ok := set.names[v.value]; !ok {
+			set.names[v.value] = v.name
+		}
+	}
+	return set
+}
+
+// nameBySpec names the values, already named after their constants, for the
+// format as the spec of a name set says.
+func (g *Generator) nameBySpec(named []Value, format, spec string) {
+	switch {
+	case spec == "linecomment":
+		g.replaceValuesWithLineComment(named)
+	case strings.HasPrefix(spec, "attr:"):
+		key := strings.TrimPrefix(spec, "attr:")
+		for i, v := range named {
+			a, ok := v.attrs[key]
+			if !ok {
+				continue
+			}
+			if a.kind != "string" {
+				fatalf("%s names: attribute %s of %s is a %s, not a string", format, key, v.constName, a.kind)
+			}
+			named[i].name, _ = strconv.Unquote(a.lit)
+		}
+	default:
+		g.applyTransform(named, spec, "")
+	}
+}
+
+// buildNameSet writes the tables and methods converting the values to and
+// from the names of the set. The names decode as FromString decodes the
+// names of String: with the case match, the deprecated hook and the checks
+// of numbers and reserved values. Under -decodeany, the names of String and
+// of the other sets decode too.
+func (g *Generator) buildNameSet(runs [][]Value, typeName string, set *nameSet, match CaseMatch) {
+	g.Printf("\nvar _%s%sNames = map[%s]string{\n", typeName, set.format, typeName)
+	for _, values := range runs {
+		for _, value := range values {
+			g.Printf("\t%s: %q,\n", &value, set.names[value.value])
+		}
+	}
+	g.Printf("}\n")
+	g.Printf("\nvar _%s%sNameToValueMap = map[string]%s{\n", typeName, set.format, typeName)
+	for _, values := range runs {
+		for _, value := range values {
+			g.Printf("\t%q: %s,\n", setKey(set.names[value.value], match), &value)
+		}
+	}
+	g.Printf("}\n")
+	tail := g.decodeTail + fmt.Sprintf(nameSetError, typeName, set.format)
+	if g.decodeAny {
+		tail = fmt.Sprintf(nameSetFallback, typeName)
+	}
+	g.Printf(nameSetMethods, typeName, set.format, setKeyCode(match), g.hookCall(runs, typeName, "val"), tail)
+}
+
+// setKey returns the name as keyed in the name to value maps of the sets:
+// folded to lower case by any case match, as the names of a set need not share
+// a case.
+func setKey(name string, match CaseMatch) string {
+	if match != CaseNone {
+		return strings.ToLower(name)
+	}
+	return name
+}
+
+// setKeyCode returns the code of the key of s in the name to value maps of
+// the sets.
+func setKeyCode(match CaseMatch) string {
+	if match != CaseNone {
+		return "strings.ToLower(s)"
+	}
+	return "s"
+}
+
+// anyNameCheck returns the code of FromString accepting the names of the
+// sets.
+func (g *Generator) anyNameCheck(runs [][]Value, typeName string, match CaseMatch) string {
+	var b strings.Builder
+	for _, set := range g.nameSets {
+		fmt.Fprintf(&b, anyNameLookup, typeName, set.format, setKeyCode(match), g.hookCall(runs, typeName, "val"))
+	}
+	return b.String()
+}
+
+// nameSetFor returns the set of the format, or nil if the format uses the
+// names of String.
+func (g *Generator) nameSetFor(format string) *nameSet {
+	for _, set := range g.nameSets {
+		if set.format == format {
+			return set
+		}
+	}
+	return nil
+}
+
+// name returns the name of the value in the set, or the name String returns
+// if the set is nil.
+func (set *nameSet) name(v Value) string {
+	if set == nil {
+		return v.name
+	}
+	return set.names[v.value]
+}
+
+// Arguments to format are:
+//	[1]: type name
+//	[2]: format
+//	[3]: key of s in the names
+//	[4]: deprecated value hook code (or "")
+//	[5]: code decoding s otherwise, ending with a return
+const nameSetMethods = `
+// %[2]sName returns the %[2]s name of i.
+func (i %[1]s) %[2]sName() string {
+	if s, ok := _%[1]s%[2]sNames[i]; ok {
+		return s
+	}
+	return fmt.Sprintf("%[1]s(%%d)", i)
+}
+
+// %[1]sFrom%[2]sName retrieves an enum value from its %[2]s name.
+// Throws an error if the param is not a %[2]s name of the enum.
+func %[1]sFrom%[2]sName(s string) (%[1]s, error) {
+	if val, ok := _%[1]s%[2]sNameToValueMap[%[3]s]; ok {
+		%[4]sreturn val, nil
+	}%[5]s
+}
+`
+
+// Arguments to format are:
+//	[1]: type name
+//	[2]: format
+const nameSetError = `
+	return 0, fmt.Errorf("%%s does not belong to %[1]s %[2]s names", s)`
+
+// Arguments to format are:
+//	[1]: type name
+const nameSetFallback = `
+	return %[1]sFromString(s)`
+
+// Arguments to format are:
+//	[1]: type name
+//	[2]: format
+//	[3]: key of s in the names
+//	[4]: deprecated value hook code (or "")
+const anyNameLookup = `
+	if val, ok := _%[1]s%[2]sNameToValueMap[%[3]s]; ok {
+		%[4]sreturn val, nil
+	}`
@@ -0,0 +1,50 @@
+// This file contains tests for the name sets of the formats.
+
+package main
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestCheckNameSetSpec(t *testing.T) {
+	for _, test := range []struct {
+		spec     string
+		expected string // A substring of the error, or "" if none.
+	}{
+		{"", ""},
+		{"snake", ""},
+		{"linecomment", ""},
+		{"attr:db", ""},
+		{"bogus", `unknown name set "bogus"`},
+		{"noop", ""},
+		{"attr", `unknown name set "attr"`},
+		{"attr:", `invalid attribute name ""`},
+		{"attr:db name", `invalid attribute name "db name"`},
+	} {
+		err := checkNameSetSpec(test.spec)
+		switch {
+		case test.expected == "" && err != nil:
+			t.Errorf("%q: unexpected error %s", test.spec, err)
+		case test.expected != "" && (err == nil || !strings.Contains(err.Error(), test.expected)):
+			t.Errorf("%q: got error %v; expected %q", test.spec, err, test.expected)
+		}
+	}
+}
+
+func TestNameSetCollisions(t *testing.T) {
+	defer func(f func(string, ...interface{})) { fatalf = f }(fatalf)
+	var got string
+	fatalf = func(format string, args ...interface{}) { got = fmt.Sprintf(format, args...) }
+	values := []Value{
+		{constName: "DayMonday", name: "DayMonday", value: 0},
+		{constName: "DayMONDAY", name: "DayMONDAY", value: 1},
+	}
+	var g Generator
+	g.newNameSet("Day", "JSON", "lower", values, "Day", CaseNone)
+	expected := "JSON names of Day collide:\n\tDayMonday and DayMONDAY are both named \"monday\""
+	if got != expected {
+		t.Errorf("got %q; expected %q", got, expected)
+	}
+}
@@ -0,0 +1,220 @@
+package main
+
+import (
+	"fmt"
+	"go/types"
+	"math"
+	"sort"
+	"strings"
+
+	"golang.org/x/tools/go/packages"
+)
+
+// protoType describes a protoc-generated Go enum type that a Go enum is
+// converted to and from.
+type protoType struct {
+	path   string            // Import path of the generated package.
+	pkg    string            // Name of the generated package.
+	name   string            // Go name of the generated type, e.g. Day or Msg_Day.
+	consts map[string]string // Proto value name (DAY_MONDAY) => Go constant name (Day_DAY_MONDAY).
+}
+
+// loadProtoType loads the protoc-generated Go type named by spec, which has
+// the form "import/path.TypeName".
+func loadProtoType(dir, spec string) *protoType {
+	dot := strings.LastIndex(spec, ".")
+	if dot <= 0 || dot == len(spec)-1 {
+		fatalf("invalid proto type %q: want import/path.TypeName", spec)
+	}
+	cfg := &packages.Config{
+		Mode: packages.NeedName | packages.NeedTypes,
+		Dir:  dir,
+	}
+	pkgs, err := packages.Load(cfg, spec[:dot])
+	if err != nil {
+		fatalf("%s", err)
+	}
+	if len(pkgs) != 1 || pkgs[0].Types == nil {
+		fatalf("cannot load package %s", spec[:dot])
+	}
+	pt, err := newProtoType(pkgs[0].Types, spec[dot+1:])
+	if err != nil {
+		fatalf("%s", err)
+	}
+	return pt
+}
+
+// newProtoType collects the constants of the named type in a type checked
+// protoc-generated package.
+func newProtoType(pkg *types.Package, typeName string) (*protoType, error) {
+	obj, ok := pkg.Scope().Lookup(typeName).(*types.TypeName)
+	if !ok {
+		return nil, fmt.Errorf("type %s not found in %s", typeName, pkg.Path())
+	}
+	// protoc-gen-go prefixes the values of a top-level enum with the type
+	// name and those of a nested enum with the name of the parent message.
+	prefixes := []string{typeName + "_"}
+	if i := strings.LastIndex(typeName, "_"); i > 0 {
+		prefixes = append(prefixes, typeName[:i+1])
+	}
+	pt := &protoType{
+		path:   pkg.Path(),
+		pkg:    pkg.Name(),
+		name:   typeName,
+		consts: make(map[string]string),
+	}
+	for _, n := range pkg.Scope().Names() {
+		c, ok := pkg.Scope().Lookup(n).(*types.Const)
+		if !ok || !types.Identical(c.Type(), obj.Type()) {
+			continue
+		}
+		for _, prefix := range prefixes {
+			if strings.HasPrefix(n, prefix) {
+				pt.consts[strings.TrimPrefix(n, prefix)] = n
+				break
+			}
+		}
+	}
+	if len(pt.consts) == 0 {
+		return nil, fmt.Errorf("no values defined for proto type %s.%s", pkg.Path(), typeName)
+	}
+	return pt, nil
+}
+
+// protoEnumPrefix returns the prefix proto style requires on every value of
+// the enum, e.g. DAY_ for Day.
+func protoEnumPrefix(typeName string, acronyms []string) string {
+	return strings.ToUpper(delimit(typeName, '_', acronyms)) + "_"
+}
+
+// protoValueName returns the proto name of a value, e.g. DAY_MONDAY for the
+// constant Monday (or DayMonday) of type Day.
+func protoValueName(typeName string, value Value, trimPrefix string, acronyms []string) string {
+	s := strings.TrimPrefix(value.constName, trimPrefix)
+	if t := strings.TrimPrefix(s, typeName); t != "" {
+		s = t
+	}
+	return protoEnumPrefix(typeName, acronyms) + strings.ToUpper(delimit(s, '_', acronyms))
+}
+
+// checkProtoValues fails unless the values of the type make a valid proto
+// enum with the same numbers: zero is the number of the TYPE_UNSPECIFIED
+// value, so a constant may only have it if proto names it so, every value
+// must fit in 32 bits, and no two values may have the same proto name.
+func checkProtoValues(runs [][]Value, typeName string, trimPrefix string, acronyms []string) error {
+	unspecified := protoEnumPrefix(typeName, acronyms) + "UNSPECIFIED"
+	owners := make(map[string]Value)
+	for _, values := range runs {
+		for _, value := range values {
+			n := protoValueName(typeName, value, trimPrefix, acronyms)
+			switch {
+			case value.value == 0 && n != unspecified:
+				return fmt.Errorf("value %s of %s is 0, the number of %s; name it %sUnspecified or start at 1", value.constName, typeName, unspecified, typeName)
+			case value.value != 0 && n == unspecified:
+				return fmt.Errorf("value %s of %s is named %s but is not 0", value.constName, typeName, unspecified)
+			case value.signed && (int64(value.value) < math.MinInt32 || int64(value.value) > math.MaxInt32),
+				!value.signed && value.value > math.MaxInt32:
+				return fmt.Errorf("value %s of %s does not fit in a proto enum", value.constName, typeName)
+			}
+			if other, ok := owners[n]; ok {
+				return fmt.Errorf("values %s and %s of %s are both named %s in proto", other.constName, value.constName, typeName, n)
+			}
+			owners[n] = value
+		}
+	}
+	return nil
+}
+
+// buildProtoEnum writes the .proto enum definition for the type. The proto
+// numbers are the Go values, the zero value first as proto3 requires.
+func (g *Generator) buildProtoEnum(runs [][]Value, typeName string, trimPrefix string) {
+	if err := checkProtoValues(runs, typeName, trimPrefix, g.acronyms); err != nil {
+		fatalf("%s", err)
+	}
+	b := &g.protoBuf
+	fmt.Fprintf(b, "\n")
+	fmt.Fprintf(b, "enum %s {\n", typeName)
+	fmt.Fprintf(b, "  %sUNSPECIFIED = 0;\n", protoEnumPrefix(typeName, g.acronyms))
+	for _, values := range runs {
+		for _, value := range values {
+			if value.value != 0 {
+				fmt.Fprintf(b, "  %s = %s;\n", protoValueName(typeName, value, trimPrefix, g.acronyms), value.str)
+			}
+		}
+	}
+	fmt.Fprintf(b, "}\n")
+}
+
+// Arguments to format are:
+//	[1]: type name
+//	[2]: qualified proto type name
+//	[3]: qualified proto unspecified value
+const protoMethods = `
+// ToProto converts %[1]s into its protoc-generated counterpart %[2]s
+func (i %[1]s) ToProto() %[2]s {
+	if v, ok := _%[1]sToProtoMap[i]; ok {
+		return v
+	}
+	return %[3]s
+}
+
+// %[1]sFromProto converts a protoc-generated %[2]s into %[1]s.
+// Throws an error if the param has no counterpart in the enum.
+func %[1]sFromProto(v %[2]s) (%[1]s, error) {
+	if i, ok := _%[1]sFromProtoMap[v]; ok {
+		return i, nil
+	}
+	return 0, fmt.Errorf("%%v does not belong to %[1]s values", v)
+}
+`
+
+// buildProtoMethods generates the conversions between the type and its
+// protoc-generated counterpart. It fails if the names do not line up.
+func (g *Generator) buildProtoMethods(runs [][]Value, typeName string, trimPrefix string, pt *protoType) {
+	if err := checkProtoValues(runs, typeName, trimPrefix, g.acronyms); err != nil {
+		fatalf("%s", err)
+	}
+	unspecified := protoEnumPrefix(typeName, g.acronyms) + "UNSPECIFIED"
+	if _, ok := pt.consts[unspecified]; !ok {
+		fatalf("%s.%s has no value %s", pt.path, pt.name, unspecified)
+	}
+	seen := map[string]bool{unspecified: true}
+	var missing []string
+	for _, values := range runs {
+		for _, value := range values {
+			n := protoValueName(typeName, value, trimPrefix, g.acronyms)
+			if _, ok := pt.consts[n]; !ok {
+				missing = append(missing, fmt.Sprintf("%s (%s)", n, value.constName))
+			}
+			seen[n] = true
+		}
+	}
+	if len(missing) > 0 {
+		fatalf("%s.%s has no values for %s", pt.path, pt.name, strings.Join(missing, ", "))
+	}
+	for n := range pt.consts {
+		if !seen[n] {
+			missing = append(missing, n)
+		}
+	}
+	if len(missing) > 0 {
+		sort.Strings(missing)
+		fatalf("%s.%s values %s have no counterpart in %s", pt.path, pt.name, strings.Join(missing, ", "), typeName)
+	}
+
+	g.Printf("\nvar _%sToProtoMap = map[%s]%s.%s{\n", typeName, typeName, pt.pkg, pt.name)
+	for _, values := range runs {
+		for _, value := range values {
+			g.Printf("\t%s: %s.%s,\n", &value, pt.pkg, pt.consts[protoValueName(typeName, value, trimPrefix, g.acronyms)])
+		}
+	}
+	g.Printf("}\n\n")
+	g.Printf("var _%sFromProtoMap = map[%s.%s]%s{\n", typeName, pt.pkg, pt.name, typeName)
+	for _, values := range runs {
+		for _, value := range values {
+			g.Printf("\t%s.%s: %s,\n", pt.pkg, pt.consts[protoValueName(typeName, value, trimPrefix, g.acronyms)], &value)
+		}
+	}
+	g.Printf("}\n")
+	g.Printf(protoMethods, typeName, pt.pkg+"."+pt.name, pt.pkg+"."+pt.consts[unspecified])
+}
@@ -0,0 +1,47 @@
+// This file contains tests for the checks of the proto enums.
+
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestCheckProtoValues(t *testing.T) {
+	day := func(constName string, value int64) Value {
+		return Value{constName: constName, value: uint64(value), signed: true}
+	}
+	tests := []struct {
+		values   []Value
+		expected string // A substring of the error, or "" if none.
+	}{
+		{[]Value{day("DayUnspecified", 0), day("Monday", 1), day("Yesterday", -1)}, ""},
+		{[]Value{day("Monday", 0), day("Tuesday", 1)}, "value Monday of Day is 0"},
+		{[]Value{day("DayUnspecified", 1)}, "is named DAY_UNSPECIFIED but is not 0"},
+		{[]Value{day("Monday", 1), day("DayMonday", 2)}, "values Monday and DayMonday of Day are both named DAY_MONDAY"},
+		{[]Value{day("Monday", 1<<31)}, "does not fit"},
+	}
+	for _, test := range tests {
+		err := checkProtoValues([][]Value{test.values}, "Day", "", nil)
+		switch {
+		case test.expected == "" && err != nil:
+			t.Errorf("%v: got error %v", test.values, err)
+		case test.expected != "" && (err == nil || !strings.Contains(err.Error(), test.expected)):
+			t.Errorf("%v: got error %v; expected %q", test.values, err, test.expected)
+		}
+	}
+}
+
+// TestProtoValueNameAcronyms checks that the proto names split the words as
+// the snakeu transform does.
+func TestProtoValueNameAcronyms(t *testing.T) {
+	acronyms := []string{"ID", "GraphQL"}
+	value := Value{constName: "APIGraphQLQueryIDs", name: "GraphQLQueryIDs"}
+	g := Generator{acronyms: acronyms}
+	values := []Value{value}
+	g.applyTransform(values, "snakeu", "")
+	expected := "API_" + values[0].name
+	if got := protoValueName("API", value, "", acronyms); got != expected {
+		t.Errorf("got %s; expected %s", got, expected)
+	}
+}
@@ -0,0 +1,300 @@
+package main
+
+import (
+	"fmt"
+	"io/ioutil"
+	"log"
+	"math"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// protoEnum is an enum declared in a .proto file.
+type protoEnum struct {
+	name          string // Go name: the proto name, qualified by enclosing messages with "_".
+	allowAlias    bool
+	values        []protoEnumValue
+	reserved      [][2]int64 // Reserved number ranges, inclusive.
+	reservedNames []string
+}
+
+// protoEnumValue is a value of an enum declared in a .proto file.
+type protoEnumValue struct {
+	name   string
+	number int64
+}
+
+// protoTokenize splits .proto source into tokens, dropping comments.
+func protoTokenize(src string) ([]string, error) {
+	var toks []string
+	for i := 0; i < len(src); {
+		c := src[i]
+		switch {
+		case c == ' ' || c == '\t' || c == '\r' || c == '\n':
+			i++
+		case strings.HasPrefix(src[i:], "//"):
+			for i < len(src) && src[i] != '\n' {
+				i++
+			}
+		case strings.HasPrefix(src[i:], "/*"):
+			end := strings.Index(src[i+2:], "*/")
+			if end < 0 {
+				return nil, fmt.Errorf("unterminated comment")
+			}
+			i += end + 4
+		case c == '"' || c == '\'':
+			j := i + 1
+			for j < len(src) && src[j] != c {
+				if src[j] == '\\' {
+					j++
+				}
+				j++
+			}
+			if j >= len(src) {
+				return nil, fmt.Errorf("unterminated string")
+			}
+			toks = append(toks, src[i:j+1])
+			i = j + 1
+		case c == '_' || c == '.' || c == '-' || c == '+' || unicode.IsLetter(rune(c)) || unicode.IsDigit(rune(c)):
+			j := i + 1
+			for j < len(src) && (src[j] == '_' || src[j] == '.' || unicode.IsLetter(rune(src[j])) || unicode.IsDigit(rune(src[j]))) {
+				j++
+			}
+			toks = append(toks, src[i:j])
+			i = j
+		default:
+			toks = append(toks, string(c))
+			i++
+		}
+	}
+	return toks, nil
+}
+
+// parseProtoInt parses a proto integer literal: decimal, hex or octal, possibly
+// signed.
+func parseProtoInt(s string) (int64, error) {
+	v, err := strconv.ParseInt(s, 0, 64)
+	if err != nil || v < math.MinInt32 || v > math.MaxInt32 {
+		return 0, fmt.Errorf("invalid enum number %q", s)
+	}
+	return v, nil
+}
+
+// parseProtoEnums returns the enums declared in the .proto source, including
+// the ones nested in messages.
+func parseProtoEnums(src string) ([]*protoEnum, error) {
+	toks, err := protoTokenize(src)
+	if err != nil {
+		return nil, err
+	}
+	var (
+		enums []*protoEnum
+		scope []string // Names of the enclosing blocks; "" for blocks other than messages.
+	)
+	for i := 0; i < len(toks); i++ {
+		switch toks[i] {
+		case "message":
+			if i+2 < len(toks) && toks[i+2] == "{" {
+				scope = append(scope, toks[i+1])
+				i += 2
+			}
+		case "enum":
+			if i+2 >= len(toks) || toks[i+2] != "{" {
+				return nil, fmt.Errorf("malformed enum declaration")
+			}
+			var names []string
+			for _, s := range scope {
+				if s != "" {
+					names = append(names, s)
+				}
+			}
+			e := &protoEnum{name: strings.Join(append(names, toks[i+1]), "_")}
+			n, err := parseProtoEnumBody(e, toks[i+3:])
+			if err != nil {
+				return nil, fmt.Errorf("enum %s: %s", toks[i+1], err)
+			}
+			enums = append(enums, e)
+			i += 2 + n
+		case "{":
+			scope = append(scope, "")
+		case "}":
+			if len(scope) == 0 {
+				return nil, fmt.Errorf("unbalanced }")
+			}
+			scope = scope[:len(scope)-1]
+		}
+	}
+	return enums, nil
+}
+
+// parseProtoEnumBody parses the statements of an enum up to its closing brace
+// and returns the number of tokens consumed.
+func parseProtoEnumBody(e *protoEnum, toks []string) (int, error) {
+	i := 0
+	// statement returns the tokens up to the next semicolon.
+	statement := func() ([]string, error) {
+		start := i
+		for i < len(toks) && toks[i] != ";" {
+			i++
+		}
+		if i == len(toks) {
+			return nil, fmt.Errorf("missing ;")
+		}
+		i++
+		return toks[start : i-1], nil
+	}
+	for i < len(toks) && toks[i] != "}" {
+		stmt, err := statement()
+		if err != nil {
+			return 0, err
+		}
+		switch {
+		case len(stmt) == 0:
+		case stmt[0] == "option":
+			if len(stmt) == 4 && stmt[1] == "allow_alias" && stmt[2] == "=" {
+				e.allowAlias = stmt[3] == "true"
+			}
+		case stmt[0] == "reserved":
+			for _, item := range strings.Split(strings.Join(stmt[1:], " "), ",") {
+				fields := strings.Fields(item)
+				switch {
+				case len(fields) == 1 && strings.HasPrefix(fields[0], "\"") || len(fields) == 1 && strings.HasPrefix(fields[0], "'"):
+					e.reservedNames = append(e.reservedNames, fields[0][1:len(fields[0])-1])
+				case len(fields) == 1:
+					n, err := parseProtoInt(fields[0])
+					if err != nil {
+						return 0, err
+					}
+					e.reserved = append(e.reserved, [2]int64{n, n})
+				case len(fields) == 3 && fields[1] == "to":
+					lo, err := parseProtoInt(fields[0])
+					if err != nil {
+						return 0, err
+					}
+					hi := int64(math.MaxInt32)
+					if fields[2] != "max" {
+						if hi, err = parseProtoInt(fields[2]); err != nil {
+							return 0, err
+						}
+					}
+					e.reserved = append(e.reserved, [2]int64{lo, hi})
+				default:
+					return 0, fmt.Errorf("malformed reserved statement")
+				}
+			}
+		case len(stmt) >= 3 && stmt[1] == "=":
+			n, err := parseProtoInt(stmt[2])
+			if err != nil {
+				return 0, err
+			}
+			e.values = append(e.values, protoEnumValue{name: stmt[0], number: n})
+		default:
+			return 0, fmt.Errorf("unexpected %q", strings.Join(stmt, " "))
+		}
+	}
+	if i == len(toks) {
+		return 0, fmt.Errorf("missing }")
+	}
+	return i + 1, e.check()
+}
+
+// check applies the rules protoc enforces on enums.
+func (e *protoEnum) check() error {
+	if len(e.values) == 0 {
+		return fmt.Errorf("no values defined")
+	}
+	names := make(map[string]bool)
+	numbers := make(map[int64]string)
+	for _, v := range e.values {
+		if names[v.name] {
+			return fmt.Errorf("value %s is declared twice", v.name)
+		}
+		names[v.name] = true
+		if other, ok := numbers[v.number]; ok && !e.allowAlias {
+			return fmt.Errorf("%s and %s use the same number %d but allow_alias is not set", other, v.name, v.number)
+		}
+		numbers[v.number] = v.name
+		for _, r := range e.reserved {
+			if v.number >= r[0] && v.number <= r[1] {
+				return fmt.Errorf("value %s uses reserved number %d", v.name, v.number)
+			}
+		}
+		for _, r := range e.reservedNames {
+			if v.name == r {
+				return fmt.Errorf("value %s uses a reserved name", v.name)
+			}
+		}
+	}
+	return nil
+}
+
+// readProtoEnums parses the named .proto file.
+func readProtoEnums(fileName string) []*protoEnum {
+	src, err := ioutil.ReadFile(fileName)
+	if err != nil {
+		log.Fatal(err)
+	}
+	enums, err := parseProtoEnums(string(src))
+	if err != nil {
+		log.Fatalf("%s: %s", fileName, err)
+	}
+	if len(enums) == 0 {
+		log.Fatalf("%s: no enums declared", fileName)
+	}
+	return enums
+}
+
+// protoEnumNames returns the Go names of the enums in the named .proto file.
+func protoEnumNames(fileName string) []string {
+	var names []string
+	for _, e := range readProtoEnums(fileName) {
+		names = append(names, e.name)
+	}
+	return names
+}
+
+// importProto defines the named types from the enums of the .proto file.
+func (g *Generator) importProto(fileName string, types []string) {
+	enums := make(map[string]*protoEnum)
+	for _, e := range readProtoEnums(fileName) {
+		enums[e.name] = e
+	}
+	for _, typeName := range types {
+		e, ok := enums[typeName]
+		if !ok {
+			log.Fatalf("%s: enum %s is not declared", fileName, typeName)
+		}
+		g.define(typeName, e.definedType(fileName))
+	}
+}
+
+// definedType turns the enum into values ready for the generator. The
+// constants are named after the type and the value stripped of the proto type
+// prefix, e.g. DayMonday for DAY_MONDAY, and print as the latter part, e.g.
+// Monday. The proto name is kept as the line comment. The reserved numbers
+// and names of the enum are reserved on the type, the names as the values
+// would print, e.g. Sunday for DAY_SUNDAY.
+func (e *protoEnum) definedType(fileName string) *definedType {
+	doc := fmt.Sprintf("%s mirrors the proto enum %s of %s.", e.name, strings.Replace(e.name, "_", ".", -1), fileName)
+	def := &definedType{underlying: "int32", doc: doc}
+	prefix := protoEnumPrefix(e.name[strings.LastIndex(e.name, "_")+1:], nil)
+	if len(e.reserved) > 0 || len(e.reservedNames) > 0 {
+		def.reserved = &reservedSet{ranges: e.reserved}
+		for _, n := range e.reservedNames {
+			def.reserved.names = append(def.reserved.names, goConstName(strings.TrimPrefix(n, prefix), e.name))
+		}
+	}
+	for _, v := range e.values {
+		short := goConstName(strings.TrimPrefix(v.name, prefix), e.name)
+		def.values = append(def.values, Value{
+			constName: e.name + short,
+			name:      short,
+			value:     uint64(v.number),
+			signed:    true,
+			str:       strconv.FormatInt(v.number, 10),
+			comment:   v.name,
+		})
+	}
+	return def
+}
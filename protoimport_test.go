@@ -0,0 +1,123 @@
+// This file contains tests for the .proto import.
+
+package main
+
+import (
+	"go/ast"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+const dayProto = `syntax = "proto3";
+
+package demo;
+
+/* Days of the week. */
+enum Day {
+  option allow_alias = true;
+  DAY_UNSPECIFIED = 0;
+  DAY_MONDAY = 1; // First.
+  DAY_MON = 1;
+  DAY_TUESDAY = 0x2 [deprecated = true];
+  reserved 5, 9 to 11, 40 to max;
+  reserved "DAY_SUNDAY";
+}
+
+message Job {
+  oneof target {
+    string host = 1;
+  }
+  enum State {
+    STATE_UNSPECIFIED = 0;
+    STATE_FAILED = -1;
+  }
+}
+`
+
+func TestParseProtoEnums(t *testing.T) {
+	enums, err := parseProtoEnums(dayProto)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := []*protoEnum{
+		{
+			name:       "Day",
+			allowAlias: true,
+			values: []protoEnumValue{
+				{"DAY_UNSPECIFIED", 0},
+				{"DAY_MONDAY", 1},
+				{"DAY_MON", 1},
+				{"DAY_TUESDAY", 2},
+			},
+			reserved:      [][2]int64{{5, 5}, {9, 11}, {40, 1<<31 - 1}},
+			reservedNames: []string{"DAY_SUNDAY"},
+		},
+		{
+			name: "Job_State",
+			values: []protoEnumValue{
+				{"STATE_UNSPECIFIED", 0},
+				{"STATE_FAILED", -1},
+			},
+		},
+	}
+	if !reflect.DeepEqual(enums, expected) {
+		t.Errorf("got %+v; expected %+v", enums, expected)
+	}
+}
+
+func TestProtoDefinedType(t *testing.T) {
+	enums, err := parseProtoEnums(dayProto)
+	if err != nil {
+		t.Fatal(err)
+	}
+	def := enums[1].definedType("day.proto")
+	expected := &definedType{
+		underlying: "int32",
+		doc:        "Job_State mirrors the proto enum Job.State of day.proto.",
+		values: []Value{
+			{constName: "Job_StateUnspecified", name: "Unspecified", value: 0, signed: true, str: "0", comment: "STATE_UNSPECIFIED"},
+			{constName: "Job_StateFailed", name: "Failed", value: 1<<64 - 1, signed: true, str: "-1", comment: "STATE_FAILED"},
+		},
+	}
+	if !reflect.DeepEqual(def, expected) {
+		t.Errorf("got %+v; expected %+v", def, expected)
+	}
+	reserved := &reservedSet{ranges: [][2]int64{{5, 5}, {9, 11}, {40, 1<<31 - 1}}, names: []string{"Sunday"}}
+	if got := enums[0].definedType("day.proto").reserved; !reflect.DeepEqual(got, reserved) {
+		t.Errorf("got reserved %+v; expected %+v", got, reserved)
+	}
+	if got, expected := reserved.directive(), `//enumer:reserved 5, 9-11, 40-2147483647, "Sunday"`; got != expected {
+		t.Errorf("got directive %s; expected %s", got, expected)
+	}
+	parsed, err := parseReserved(&ast.CommentGroup{List: []*ast.Comment{{Text: reserved.directive()}}})
+	if err != nil || !reflect.DeepEqual(parsed, reserved) {
+		t.Errorf("parsed directive as %+v, %v; expected %+v", parsed, err, reserved)
+	}
+}
+
+var badProtoEnums = []struct {
+	src string
+	err string
+}{
+	{`enum A { A_X = 1; A_Y = 1; }`, "allow_alias is not set"},
+	{`enum A { A_X = 1; A_X = 2; }`, "declared twice"},
+	{`enum A { A_X = 5; reserved 4 to 6; }`, "reserved number 5"},
+	{`enum A { A_X = 1; reserved "A_X"; }`, "reserved name"},
+	{`enum A { }`, "no values defined"},
+	{`enum A { A_X = 1 }`, "missing ;"},
+	{`enum A { A_X = 1;`, "missing }"},
+	{`enum A { A_X = 4294967296; }`, "invalid enum number"},
+	{`enum A { reserved 1 2; A_X = 0; }`, "malformed reserved"},
+	{`enum A { A_X; }`, "unexpected"},
+	{`message M { /* }`, "unterminated comment"},
+}
+
+func TestBadProtoEnums(t *testing.T) {
+	for _, test := range badProtoEnums {
+		_, err := parseProtoEnums(test.src)
+		if err == nil || !strings.Contains(err.Error(), test.err) {
+			t.Errorf("%s: got error %v; expected %q", test.src, err, test.err)
+		}
+	}
+}
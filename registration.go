@@ -0,0 +1,57 @@
+package main
+
+import (
+	"golang.org/x/tools/go/packages"
+)
+
+// Import paths of the runtime packages.
+const (
+	registryPath = "github.com/capsule8/enumer/registry"
+	enumerPath   = "github.com/capsule8/enumer/enumer"
+)
+
+// packagePath returns the import path of the package in the directory, for
+// types not read from a loaded package. It falls back to the package name.
+func packagePath(dir, name string) string {
+	pkgs, err := packages.Load(&packages.Config{Mode: packages.NeedName, Dir: dir}, ".")
+	if err != nil || len(pkgs) != 1 || pkgs[0].PkgPath == "" || pkgs[0].PkgPath == "command-line-arguments" {
+		return name
+	}
+	return pkgs[0].PkgPath
+}
+
+// buildRegistration writes the init function registering the type.
+func (g *Generator) buildRegistration(typeName string) {
+	g.Printf(registryInit, typeName, g.pkg.path+"."+typeName)
+}
+
+// Arguments to format are:
+//	[1]: type name
+//	[2]: fully qualified type name
+const registryInit = `
+func init() {
+	registry.Register(%[2]q, %[1]sValues, %[1]sFromString, %[1]s.IsA%[1]s)
+}
+`
+
+// buildEnumDescriptor writes the EnumDescriptor method making the type an
+// enumer.Enum.
+func (g *Generator) buildEnumDescriptor(typeName string) {
+	g.Printf(enumDescriptor, typeName)
+}
+
+// Arguments to format are:
+//	[1]: type name
+const enumDescriptor = `
+var _%[1]sEnumDescriptor = enumer.Descriptor[%[1]s]{
+	Name:    "%[1]s",
+	Values:  %[1]sValues,
+	Parse:   %[1]sFromString,
+	IsValid: %[1]s.IsA%[1]s,
+}
+
+// EnumDescriptor returns the functions of %[1]s used by the generic helpers of the enumer package
+func (%[1]s) EnumDescriptor() enumer.Descriptor[%[1]s] {
+	return _%[1]sEnumDescriptor
+}
+`
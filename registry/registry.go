@@ -0,0 +1,121 @@
+// Package registry records the enum types generated by enumer with the
+// -registry flag, so that they can be listed, and their values parsed and
+// formatted, given only the name of the type.
+//
+// Generated files register their types from init functions, under the fully
+// qualified type name, e.g. "github.com/capsule8/enumer/registry_test.Color".
+package registry
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+)
+
+// Enum is the common interface to a registered enum type.
+type Enum interface {
+	// Name returns the fully qualified name of the type.
+	Name() string
+	// Values returns the values of the type, each a value of the type itself.
+	Values() []fmt.Stringer
+	// Names returns the names of the values, in the order of Values.
+	Names() []string
+	// Parse returns the value with the given name.
+	Parse(s string) (fmt.Stringer, error)
+	// Format returns the name of v. It fails if v is not a value of the type.
+	Format(v interface{}) (string, error)
+}
+
+// enum implements Enum with the generated functions of the type T.
+type enum[T fmt.Stringer] struct {
+	name   string
+	values func() []T
+	parse  func(string) (T, error)
+	isA    func(T) bool
+}
+
+func (e *enum[T]) Name() string { return e.name }
+
+func (e *enum[T]) Values() []fmt.Stringer {
+	values := e.values()
+	vs := make([]fmt.Stringer, len(values))
+	for i, v := range values {
+		vs[i] = v
+	}
+	return vs
+}
+
+func (e *enum[T]) Names() []string {
+	values := e.values()
+	names := make([]string, len(values))
+	for i, v := range values {
+		names[i] = v.String()
+	}
+	return names
+}
+
+func (e *enum[T]) Parse(s string) (fmt.Stringer, error) {
+	v, err := e.parse(s)
+	if err != nil {
+		return nil, err
+	}
+	return v, nil
+}
+
+func (e *enum[T]) Format(v interface{}) (string, error) {
+	t, ok := v.(T)
+	if !ok {
+		return "", fmt.Errorf("%T is not a %s", v, e.name)
+	}
+	if !e.isA(t) {
+		return "", fmt.Errorf("%s is not a %s value", t, e.name)
+	}
+	return t.String(), nil
+}
+
+var (
+	mu    sync.RWMutex
+	enums = make(map[string]Enum)
+)
+
+// Register records the enum type T under its fully qualified name, with the
+// generated functions listing, parsing and checking its values. It panics if
+// the name is registered already.
+func Register[T fmt.Stringer](name string, values func() []T, parse func(string) (T, error), isA func(T) bool) {
+	mu.Lock()
+	defer mu.Unlock()
+	if _, ok := enums[name]; ok {
+		panic("registry: enum " + name + " registered twice")
+	}
+	enums[name] = &enum[T]{name: name, values: values, parse: parse, isA: isA}
+}
+
+// Lookup returns the enum type with the fully qualified name.
+func Lookup(name string) (Enum, bool) {
+	mu.RLock()
+	defer mu.RUnlock()
+	e, ok := enums[name]
+	return e, ok
+}
+
+// All returns the registered enum types, sorted by name.
+func All() []Enum {
+	mu.RLock()
+	defer mu.RUnlock()
+	all := make([]Enum, 0, len(enums))
+	for _, e := range enums {
+		all = append(all, e)
+	}
+	sort.Slice(all, func(i, j int) bool { return all[i].Name() < all[j].Name() })
+	return all
+}
+
+// Parse returns the value with the given name of the enum type with the fully
+// qualified name typeName.
+func Parse(typeName, s string) (fmt.Stringer, error) {
+	e, ok := Lookup(typeName)
+	if !ok {
+		return nil, fmt.Errorf("registry: unknown enum %s", typeName)
+	}
+	return e.Parse(s)
+}
@@ -0,0 +1,105 @@
+package registry_test
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/capsule8/enumer/registry"
+)
+
+// Color is registered the way generated files do.
+type Color int
+
+const (
+	Red Color = iota
+	Green
+)
+
+var colorNames = map[Color]string{Red: "Red", Green: "Green"}
+
+func (c Color) String() string {
+	if s, ok := colorNames[c]; ok {
+		return s
+	}
+	return fmt.Sprintf("Color(%d)", int(c))
+}
+
+func ColorValues() []Color { return []Color{Red, Green} }
+
+func ColorFromString(s string) (Color, error) {
+	for c, name := range colorNames {
+		if name == s {
+			return c, nil
+		}
+	}
+	return 0, fmt.Errorf("%s does not belong to Color values", s)
+}
+
+func (c Color) IsAColor() bool {
+	_, ok := colorNames[c]
+	return ok
+}
+
+const colorName = "github.com/capsule8/enumer/registry_test.Color"
+
+func init() {
+	registry.Register(colorName, ColorValues, ColorFromString, Color.IsAColor)
+}
+
+func TestLookup(t *testing.T) {
+	e, ok := registry.Lookup(colorName)
+	if !ok {
+		t.Fatalf("%s is not registered", colorName)
+	}
+	if got := e.Names(); !reflect.DeepEqual(got, []string{"Red", "Green"}) {
+		t.Errorf("got names %v", got)
+	}
+	if got := e.Values(); !reflect.DeepEqual(got, []fmt.Stringer{Red, Green}) {
+		t.Errorf("got values %v", got)
+	}
+	if _, ok := registry.Lookup("Color"); ok {
+		t.Errorf("unqualified name found")
+	}
+	if all := registry.All(); len(all) != 1 || all[0] != e {
+		t.Errorf("got all %v", all)
+	}
+}
+
+func TestParseAndFormat(t *testing.T) {
+	v, err := registry.Parse(colorName, "Green")
+	if err != nil || v != Green {
+		t.Errorf("got %v, %v; expected Green", v, err)
+	}
+	if _, err := registry.Parse(colorName, "Blue"); err == nil {
+		t.Errorf("Blue parsed")
+	}
+	if _, err := registry.Parse("example.com/Color", "Green"); err == nil {
+		t.Errorf("unknown enum parsed")
+	}
+
+	e, _ := registry.Lookup(colorName)
+	for _, test := range []struct {
+		v    interface{}
+		name string
+		ok   bool
+	}{
+		{Red, "Red", true},
+		{Color(7), "", false},
+		{7, "", false},
+	} {
+		name, err := e.Format(test.v)
+		if name != test.name || (err == nil) != test.ok {
+			t.Errorf("%v: got %q, %v", test.v, name, err)
+		}
+	}
+}
+
+func TestRegisterTwice(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Errorf("no panic")
+		}
+	}()
+	registry.Register(colorName, ColorValues, ColorFromString, Color.IsAColor)
+}
@@ -0,0 +1,257 @@
+package main
+
+import (
+	"fmt"
+	"go/ast"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// reservedDirective, on the type declaration, retires numbers and names, as
+// in .proto files, e.g.
+//
+//	//enumer:reserved 5, 7-9, "Legacy"
+//
+// No constant may use them, and decoding them fails with a dedicated error.
+const reservedDirective = "//enumer:reserved"
+
+// reservedSet holds the numbers and names reserved for a type.
+type reservedSet struct {
+	ranges  [][2]int64 // Inclusive.
+	names   []string
+	decoded []string // The names as decoded, trimmed and transformed as the names of the values.
+
+	setDecoded map[string][]string // The names as the name sets decode them, by format.
+}
+
+// parseReserved returns the numbers and names reserved by the enumer:reserved
+// directives of the comment groups, or nil if there are none.
+func parseReserved(groups ...*ast.CommentGroup) (*reservedSet, error) {
+	var set *reservedSet
+	for _, group := range groups {
+		if group == nil {
+			continue
+		}
+		for _, c := range group.List {
+			if !strings.HasPrefix(c.Text, reservedDirective+" ") {
+				continue
+			}
+			if set == nil {
+				set = new(reservedSet)
+			}
+			for _, item := range strings.Split(strings.TrimPrefix(c.Text, reservedDirective), ",") {
+				item = strings.TrimSpace(item)
+				if strings.HasPrefix(item, `"`) {
+					name, err := strconv.Unquote(item)
+					if err != nil || name == "" {
+						return nil, fmt.Errorf("malformed reserved name %s", item)
+					}
+					set.names = append(set.names, name)
+					continue
+				}
+				lo, hi := item, item
+				// A dash after the first character separates a range.
+				if i := strings.Index(item[min(1, len(item)):], "-"); i >= 0 {
+					lo, hi = item[:i+1], item[i+2:]
+				}
+				l, err := strconv.ParseInt(strings.TrimSpace(lo), 0, 64)
+				if err != nil {
+					return nil, fmt.Errorf("malformed reserved number %q", item)
+				}
+				h, err := strconv.ParseInt(strings.TrimSpace(hi), 0, 64)
+				if err != nil || h < l {
+					return nil, fmt.Errorf("malformed reserved range %q", item)
+				}
+				set.ranges = append(set.ranges, [2]int64{l, h})
+			}
+		}
+	}
+	return set, nil
+}
+
+// directive returns the enumer:reserved directive reserving the set.
+func (r *reservedSet) directive() string {
+	var items []string
+	for _, rg := range r.ranges {
+		if rg[0] == rg[1] {
+			items = append(items, strconv.FormatInt(rg[0], 10))
+		} else {
+			items = append(items, fmt.Sprintf("%d-%d", rg[0], rg[1]))
+		}
+	}
+	for _, name := range r.names {
+		items = append(items, strconv.Quote(name))
+	}
+	return reservedDirective + " " + strings.Join(items, ", ")
+}
+
+// typeReserved returns the numbers and names reserved on the type, or nil.
+func (g *Generator) typeReserved(typeName string) *reservedSet {
+	if def, ok := g.defined[typeName]; ok {
+		return def.reserved
+	}
+	set, err := parseReserved(g.typeDocGroup(typeName))
+	if err != nil {
+		fatalf("type %s: %s", typeName, err)
+	}
+	return set
+}
+
+// decodeReserved sets the decoded names of the set by trimming the prefix
+// from the names and transforming them, as for the names of the values, and
+// as the name sets of the type name them.
+func (g *Generator) decodeReserved(r *reservedSet, trimPrefix, transformMethod string) {
+	named := func() []Value {
+		named := make([]Value, len(r.names))
+		for i, name := range r.names {
+			named[i].constName, named[i].name = name, name
+		}
+		g.trimValueNames(named, trimPrefix)
+		return named
+	}
+	names := func(named []Value) []string {
+		decoded := make([]string, len(named))
+		for i, v := range named {
+			decoded[i] = v.name
+		}
+		return decoded
+	}
+	values := named()
+	g.transformValueNames(values, transformMethod, "")
+	r.decoded = names(values)
+	r.setDecoded = make(map[string][]string)
+	for _, set := range g.nameSets {
+		values := named()
+		g.nameBySpec(values, set.format, set.spec)
+		r.setDecoded[set.format] = names(values)
+	}
+}
+
+// reservesNumber reports whether the value is in a reserved range.
+func (r *reservedSet) reservesNumber(v Value) bool {
+	if !v.signed && v.value > math.MaxInt64 {
+		return false
+	}
+	n := int64(v.value)
+	for _, rg := range r.ranges {
+		if n >= rg[0] && n <= rg[1] {
+			return true
+		}
+	}
+	return false
+}
+
+// check fails if a constant of the type uses a reserved number, or a
+// reserved name as its identifier or, once decoded, as its string
+// representation or its name in one of the sets.
+func (r *reservedSet) check(typeName string, values []Value, sets []*nameSet) {
+	for _, v := range values {
+		if r.reservesNumber(v) {
+			fatalf("%s of %s uses the reserved number %s", v.constName, typeName, v.str)
+		}
+		for i, name := range r.names {
+			if v.constName == name || v.name == r.decoded[i] {
+				fatalf("%s of %s uses the reserved name %q", v.constName, typeName, name)
+			}
+			for _, set := range sets {
+				if set.names[v.value] == r.setDecoded[set.format][i] {
+					fatalf("%s of %s uses the reserved name %q as its %s name", v.constName, typeName, name, set.format)
+				}
+			}
+		}
+	}
+}
+
+// buildReserved writes the error returned when decoding reserved values, the
+// set of decoded reserved names, as String and the name sets decode them,
+// keyed by the decoding with the case match, and the check of reserved
+// numbers. It returns the code checking s in FromString.
+func (g *Generator) buildReserved(r *reservedSet, typeName string, ignoreCase CaseMatch, numeric bool) string {
+	g.Printf(reservedError, typeName)
+	var check strings.Builder
+	if len(r.decoded) > 0 {
+		key := "s"
+		switch ignoreCase {
+		case CaseLower, CaseMixed:
+			key = "strings.ToLower(s)"
+		case CaseUpper:
+			key = "strings.ToUpper(s)"
+		}
+		g.Printf("\nvar _%sReservedNames = map[string]struct{}{\n", typeName)
+		seen := make(map[string]bool)
+		for _, names := range append([][]string{r.decoded}, r.setDecodedNames(g.nameSets)...) {
+			for _, name := range names {
+				if name = ignoreCase.fold(name); !seen[name] {
+					g.Printf("\t%q: {},\n", name)
+					seen[name] = true
+				}
+			}
+		}
+		g.Printf("}\n")
+		fmt.Fprintf(&check, reservedNameCheck, typeName, key)
+	}
+	if len(r.ranges) > 0 && (numeric || g.fromInt) {
+		var conds []string
+		for _, rg := range r.ranges {
+			if rg[0] == rg[1] {
+				conds = append(conds, fmt.Sprintf("i == %d", rg[0]))
+			} else {
+				conds = append(conds, fmt.Sprintf("%d <= i && i <= %d", rg[0], rg[1]))
+			}
+		}
+		g.Printf(reservedNumberFunc, typeName, strings.Join(conds, " || "))
+		if numeric {
+			fmt.Fprintf(&check, reservedNumericCheck, typeName)
+		}
+	}
+	return check.String()
+}
+
+// setDecodedNames returns the names as the sets decode them, in the order of
+// the sets.
+func (r *reservedSet) setDecodedNames(sets []*nameSet) [][]string {
+	var names [][]string
+	for _, set := range sets {
+		names = append(names, r.setDecoded[set.format])
+	}
+	return names
+}
+
+// Arguments to format are:
+//	[1]: type name
+const reservedError = `
+// Err%[1]sReserved is wrapped by the errors returned when decoding a reserved number or name of %[1]s.
+var Err%[1]sReserved = errors.New("reserved %[1]s value")
+`
+
+// Arguments to format are:
+//	[1]: type name
+//	[2]: condition on i
+const reservedNumberFunc = `
+func _%[1]sIsReserved(i int64) bool {
+	return %[2]s
+}
+`
+
+// Arguments to format are:
+//	[1]: type name
+//	[2]: key of s in the reserved names
+const reservedNameCheck = `
+        if _, ok := _%[1]sReservedNames[%[2]s]; ok {
+                return 0, fmt.Errorf("%%s: %%w", s, Err%[1]sReserved)
+        }`
+
+// Arguments to format are:
+//	[1]: type name
+const reservedNumericCheck = `
+        if i, err := strconv.Atoi(s); err == nil && _%[1]sIsReserved(int64(i)) {
+                return 0, fmt.Errorf("%%s: %%w", s, Err%[1]sReserved)
+        }`
+
+// Arguments to format are:
+//	[1]: type name
+const reservedFromIntCheck = `if _%[1]sIsReserved(int64(i)) {
+		return 0, fmt.Errorf("%%d: %%w", i, Err%[1]sReserved)
+	}
+	`
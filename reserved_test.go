@@ -0,0 +1,42 @@
+// This file contains tests for the enumer:reserved comments.
+
+package main
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseReserved(t *testing.T) {
+	set, err := parseReserved(commentGroup("// Plan is a plan.", `//enumer:reserved 5, 7-9, "Legacy"`, "//enumer:reserved -3--1, 0x10"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := &reservedSet{
+		ranges: [][2]int64{{5, 5}, {7, 9}, {-3, -1}, {16, 16}},
+		names:  []string{"Legacy"},
+	}
+	if !reflect.DeepEqual(set, expected) {
+		t.Errorf("got %+v; expected %+v", set, expected)
+	}
+	if set, err := parseReserved(commentGroup("// Plan is a plan.")); set != nil || err != nil {
+		t.Errorf("got %+v, %v without directive", set, err)
+	}
+}
+
+func TestBadReserved(t *testing.T) {
+	for line, expected := range map[string]string{
+		"//enumer:reserved 5,":      "malformed reserved number",
+		"//enumer:reserved 9-7":     "malformed reserved range",
+		"//enumer:reserved 7-x":     "malformed reserved range",
+		`//enumer:reserved "Legacy`: "malformed reserved name",
+		`//enumer:reserved ""`:      "malformed reserved name",
+		"//enumer:reserved five":    "malformed reserved number",
+	} {
+		_, err := parseReserved(commentGroup(line))
+		if err == nil || !strings.Contains(err.Error(), expected) {
+			t.Errorf("%s: got error %v; expected %q", line, err, expected)
+		}
+	}
+}
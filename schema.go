@@ -0,0 +1,107 @@
+package main
+
+import (
+	jsonenc "encoding/json"
+	"log"
+	"strconv"
+	"strings"
+)
+
+// jsonSchemaDraft is the JSON Schema dialect of the written documents.
+const jsonSchemaDraft = "https://json-schema.org/draft/2020-12/schema"
+
+// jsonSchema is the JSON Schema, also valid as an OpenAPI 3 schema object,
+// describing the values of an enum.
+type jsonSchema struct {
+	Schema       string        `json:"$schema,omitempty"`
+	Title        string        `json:"title"`
+	Description  string        `json:"description,omitempty"`
+	Type         string        `json:"type"`
+	Enum         []interface{} `json:"enum"`
+	VarNames     []string      `json:"x-enum-varnames"`
+	Descriptions []string      `json:"x-enum-descriptions,omitempty"`
+}
+
+// newJSONSchema describes the values of the type as they are encoded by
+// encoding/json: by name if the type marshals itself as a string, by number
+// otherwise. The names are the ones of the set, or of String if it is nil.
+func (g *Generator) newJSONSchema(runs [][]Value, typeName string, asString bool, names *nameSet) *jsonSchema {
+	s := &jsonSchema{
+		Title:       typeName,
+		Description: g.typeDoc(typeName),
+		Type:        "integer",
+	}
+	if asString {
+		s.Type = "string"
+	}
+	var described bool
+	for _, values := range runs {
+		for _, value := range values {
+			if value.deprecated() {
+				// Still accepted, but no longer to be produced.
+				continue
+			}
+			if asString {
+				s.Enum = append(s.Enum, names.name(value))
+			} else {
+				s.Enum = append(s.Enum, jsonenc.Number(value.str))
+			}
+			s.VarNames = append(s.VarNames, value.constName)
+			s.Descriptions = append(s.Descriptions, value.doc)
+			described = described || value.doc != ""
+		}
+	}
+	if !described {
+		s.Descriptions = nil
+	}
+	return s
+}
+
+// document returns the schema as a standalone JSON Schema document.
+func (s *jsonSchema) document() []byte {
+	doc := *s
+	doc.Schema = jsonSchemaDraft
+	b, err := jsonenc.MarshalIndent(&doc, "", "  ")
+	if err != nil {
+		log.Fatalf("encoding JSON Schema of %s: %s", s.Title, err)
+	}
+	return append(b, '\n')
+}
+
+// openAPIComponents returns an OpenAPI 3 document fragment holding the
+// schemas as components.
+func openAPIComponents(schemas []*jsonSchema) []byte {
+	var b strings.Builder
+	b.WriteString("{\n  \"components\": {\n    \"schemas\": {")
+	for i, s := range schemas {
+		if i > 0 {
+			b.WriteString(",")
+		}
+		js, err := jsonenc.MarshalIndent(s, "      ", "  ")
+		if err != nil {
+			log.Fatalf("encoding OpenAPI schema of %s: %s", s.Title, err)
+		}
+		b.WriteString("\n      " + strconv.Quote(s.Title) + ": ")
+		b.Write(js)
+	}
+	b.WriteString("\n    }\n  }\n}\n")
+	return []byte(b.String())
+}
+
+// Arguments to format are:
+//	[1]: type name
+const jsonSchemaMethod = `
+// JSONSchema returns the JSON Schema describing the values of %[1]s
+func (%[1]s) JSONSchema() json.RawMessage {
+	return json.RawMessage(_%[1]sJSONSchema)
+}
+`
+
+func (g *Generator) buildJSONSchemaMethod(typeName string, s *jsonSchema) {
+	b, err := jsonenc.Marshal(s)
+	if err != nil {
+		fatalf("encoding JSON Schema of %s: %s", typeName, err)
+	}
+	g.Printf("\nconst _%sJSONSchema = %q\n", typeName, b)
+	g.Printf(jsonSchemaMethod, typeName)
+}
@@ -2,11 +2,15 @@ package main
 
 // Arguments to format are:
 //	[1]: type name
+//	[2]: method returning the name to store
 const valueMethod = `func (i %[1]s) Value() (driver.Value, error) {
-	return i.String(), nil
+	return i.%[2]s(), nil
 }
 `
 
+// Arguments to format are:
+//	[1]: type name
+//	[2]: function decoding the name
 const scanMethod = `func (i *%[1]s) Scan(value interface{}) error {
 	if value == nil {
 		return nil
@@ -22,7 +26,7 @@ const scanMethod = `func (i *%[1]s) Scan(value interface{}) error {
 		str = string(bytes[:])
 	}
 
-	val, err := %[1]sString(str)
+	val, err := %[2]s(str)
 	if err != nil {
 		return err
 	}
@@ -34,7 +38,11 @@ const scanMethod = `func (i *%[1]s) Scan(value interface{}) error {
 
 func (g *Generator) addValueAndScanMethod(typeName string) {
 	g.Printf("\n")
-	g.Printf(valueMethod, typeName)
+	name, decode := "String", typeName+"String"
+	if g.nameSetFor("SQL") != nil {
+		name, decode = "SQLName", typeName+"FromSQLName"
+	}
+	g.Printf(valueMethod, typeName, name)
 	g.Printf("\n\n")
-	g.Printf(scanMethod, typeName)
+	g.Printf(scanMethod, typeName, decode)
 }
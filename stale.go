@@ -0,0 +1,256 @@
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"go/ast"
+	"go/types"
+	"io/ioutil"
+	"log"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/analysis/singlechecker"
+)
+
+// staleAnalyzer reports files generated by enumer that differ from what the
+// command line recorded in their header generates from the package as it is
+// now, e.g. after a constant was added. It reruns the generator in memory and
+// suggests the new contents as a fix.
+var staleAnalyzer = &analysis.Analyzer{
+	Name: "enumerstale",
+	Doc: `report enumer-generated files that are out of date
+
+The analyzer reruns enumer in memory for each file generated by it, with the
+flags recorded in the "Code generated" header, and reports the file if the
+result differs. The suggested fix replaces the file contents.`,
+	Run: runStale,
+}
+
+// enumerFlags holds the flags of the generator, without the ones of the
+// checker or of tests.
+var enumerFlags = flag.NewFlagSet("enumer", flag.ContinueOnError)
+
+// staleMu serializes the regenerations, as the generator is configured
+// through the global flags.
+var staleMu sync.Mutex
+
+// runStaleChecker runs the stale analyzer on the packages named by the
+// arguments, or as the vet tool when invoked by "go vet -vettool".
+func runStaleChecker() {
+	if os.Args[1] == "stale" {
+		os.Args = append(os.Args[:1], os.Args[2:]...)
+	}
+	flag.CommandLine = flag.NewFlagSet(os.Args[0], flag.ExitOnError)
+	singlechecker.Main(staleAnalyzer)
+}
+
+// isVetTool reports whether the arguments are the ones go vet passes to its
+// -vettool.
+func isVetTool(args []string) bool {
+	for _, arg := range args {
+		if arg == "-V=full" || arg == "-flags" || strings.HasSuffix(arg, ".cfg") {
+			return true
+		}
+	}
+	return false
+}
+
+// commandLine returns the arguments as recorded in the headers of the
+// generated files: separated by spaces, the ones that are empty or hold
+// spaces or quotes quoted as Go strings.
+func commandLine(args []string) string {
+	quoted := make([]string, len(args))
+	for i, arg := range args {
+		quoted[i] = arg
+		if arg == "" || strings.ContainsAny(arg, " \t\"") || !strconv.CanBackquote(arg) {
+			quoted[i] = strconv.Quote(arg)
+		}
+	}
+	return strings.Join(quoted, " ")
+}
+
+// splitCommandLine returns the arguments recorded by commandLine.
+func splitCommandLine(s string) ([]string, error) {
+	var args []string
+	for s = strings.TrimLeft(s, " "); s != ""; s = strings.TrimLeft(s, " ") {
+		arg := s
+		if i := strings.IndexByte(s, ' '); i >= 0 {
+			arg = s[:i]
+		}
+		if s[0] == '"' {
+			quoted, err := strconv.QuotedPrefix(s)
+			if err != nil {
+				return nil, fmt.Errorf("malformed argument %s", s)
+			}
+			if arg, err = strconv.Unquote(quoted); err != nil {
+				return nil, err
+			}
+			if s = s[len(quoted):]; s != "" && s[0] != ' ' {
+				return nil, fmt.Errorf("malformed argument %s", quoted+s)
+			}
+		} else {
+			s = s[len(arg):]
+		}
+		args = append(args, arg)
+	}
+	return args, nil
+}
+
+// generatedCommand returns the arguments of enumer recorded in the header of
+// the file, if it was generated by enumer and they can be read back.
+func generatedCommand(file *ast.File) ([]string, bool) {
+	const prefix, suffix = `// Code generated by "enumer `, `"; DO NOT EDIT.`
+	for _, group := range file.Comments {
+		if group.Pos() >= file.Package {
+			break
+		}
+		for _, c := range group.List {
+			if strings.HasPrefix(c.Text, prefix) && strings.HasSuffix(c.Text, suffix) {
+				args, err := splitCommandLine(c.Text[len(prefix) : len(c.Text)-len(suffix)])
+				return args, err == nil
+			}
+		}
+	}
+	return nil, false
+}
+
+func runStale(pass *analysis.Pass) (interface{}, error) {
+	for _, file := range pass.Files {
+		args, ok := generatedCommand(file)
+		if !ok {
+			continue
+		}
+		fileName := pass.Fset.File(file.Pos()).Name()
+		src, err := regenerate(pass, args, filepath.Dir(fileName))
+		if err != nil {
+			pass.Reportf(file.Package, "cannot regenerate with enumer %s: %s", commandLine(args), err)
+			continue
+		}
+		if src == nil {
+			// Generated from other sources than the package.
+			continue
+		}
+		old, err := readFile(pass, fileName)
+		if err != nil {
+			return nil, err
+		}
+		if bytes.Equal(old, src) {
+			continue
+		}
+		pass.Report(analysis.Diagnostic{
+			Pos:     file.Package,
+			Message: fmt.Sprintf("%s is out of date; run enumer %s", filepath.Base(fileName), commandLine(args)),
+			SuggestedFixes: []analysis.SuggestedFix{{
+				Message:   "Regenerate " + filepath.Base(fileName),
+				TextEdits: []analysis.TextEdit{{Pos: file.FileStart, End: file.FileEnd, NewText: src}},
+			}},
+		})
+	}
+	return nil, nil
+}
+
+// readFile returns the contents of the file, through the driver if it
+// provides them.
+func readFile(pass *analysis.Pass, fileName string) ([]byte, error) {
+	if pass.ReadFile != nil {
+		return pass.ReadFile(fileName)
+	}
+	return ioutil.ReadFile(fileName)
+}
+
+// setFlags sets the flags of the generator from the arguments recorded in a
+// generated file, the other ones going back to their defaults.
+func setFlags(args []string) error {
+	enumerFlags.SetOutput(ioutil.Discard)
+	enumerFlags.VisitAll(func(f *flag.Flag) { f.Value.Set(f.DefValue) })
+	comments = nil
+	transformRules = nil
+	return enumerFlags.Parse(args)
+}
+
+// generationError is an error of the generator, run by the analyzer.
+type generationError string
+
+func (e generationError) Error() string {
+	return string(e)
+}
+
+// regenerate returns the source enumer generates for the package of the pass
+// with the arguments. It returns nil if the types are not declared by Go code
+// of the package.
+func regenerate(pass *analysis.Pass, args []string, dir string) (src []byte, err error) {
+	if len(args) > 0 && (args[0] == "import-c" || args[0] == "import-proto") {
+		return nil, nil
+	}
+
+	staleMu.Lock()
+	defer staleMu.Unlock()
+
+	// Turn the errors of the generator into the error of the regeneration.
+	fatalf = func(format string, args ...interface{}) {
+		panic(generationError(fmt.Sprintf(format, args...)))
+	}
+	defer func() {
+		fatalf = log.Fatalf
+		r := recover()
+		if e, ok := r.(generationError); ok {
+			src, err = nil, e
+		} else if r != nil {
+			panic(r)
+		}
+	}()
+
+	if err := setFlags(args); err != nil {
+		return nil, err
+	}
+	if *fromFile != "" || *platforms != "" {
+		return nil, nil
+	}
+	if *typeNames == "" {
+		return nil, fmt.Errorf("no -type")
+	}
+	types := strings.Split(*typeNames, ",")
+	for _, typeName := range types {
+		if err := checkDeclared(pass.Pkg, typeName); err != nil {
+			return nil, err
+		}
+	}
+
+	g := Generator{pkg: &Package{
+		name:     pass.Pkg.Name(),
+		path:     pass.Pkg.Path(),
+		fset:     pass.Fset,
+		defs:     pass.TypesInfo.Defs,
+		files:    make([]*File, len(pass.Files)),
+		typesPkg: pass.Pkg,
+	}}
+	for i, file := range pass.Files {
+		g.pkg.files[i] = &File{file: file, pkg: g.pkg}
+	}
+	pkgDir := dir
+	if *output != "" {
+		pkgDir = filepath.Dir(*output)
+	}
+	return g.generateSource(types, dir, pkgDir, *trimPrefix, commandLine(args)), nil
+}
+
+// checkDeclared checks that the package declares constants of the named type,
+// as the generator gives up otherwise.
+func checkDeclared(pkg *types.Package, typeName string) error {
+	obj, ok := pkg.Scope().Lookup(typeName).(*types.TypeName)
+	if !ok {
+		return fmt.Errorf("type %s is not declared", typeName)
+	}
+	for _, name := range pkg.Scope().Names() {
+		if c, ok := pkg.Scope().Lookup(name).(*types.Const); ok && types.Identical(c.Type(), obj.Type()) {
+			return nil
+		}
+	}
+	return fmt.Errorf("no values defined for type %s", typeName)
+}
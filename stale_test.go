@@ -0,0 +1,31 @@
+// This file contains a test for the analyzer reporting stale generated files.
+
+package main
+
+import (
+	"reflect"
+	"testing"
+
+	"golang.org/x/tools/go/analysis/analysistest"
+)
+
+func TestStaleAnalyzer(t *testing.T) {
+	testdata := analysistest.TestData()
+	analysistest.RunWithSuggestedFixes(t, testdata, staleAnalyzer, "day", "fresh", "collide", "badproto")
+}
+
+func TestCommandLine(t *testing.T) {
+	args := []string{"-type", "Day", "-comment", "Days of the week.", "-transform-rule", `s/Pct/ "percent"\t/`, "-empty", ""}
+	line := commandLine(args)
+	expected := `-type Day -comment "Days of the week." -transform-rule "s/Pct/ \"percent\"\\t/" -empty ""`
+	if line != expected {
+		t.Errorf("got %s; expected %s", line, expected)
+	}
+	got, err := splitCommandLine(line)
+	if err != nil || !reflect.DeepEqual(got, args) {
+		t.Errorf("got %q, %v; expected %q", got, err, args)
+	}
+	if _, err := splitCommandLine(`-comment "Days`); err == nil {
+		t.Errorf("got no error for an unterminated quote")
+	}
+}
@@ -18,6 +18,7 @@ import (
 	exact "go/constant"
 	"go/format"
 	"go/importer"
+	"go/parser"
 	"go/token"
 	"go/types"
 	"io/ioutil"
@@ -26,8 +27,8 @@ import (
 	"path/filepath"
 	"sort"
 	"strings"
+	"unicode"
 
-	"github.com/pascaldekloe/name"
 	"golang.org/x/tools/go/packages"
 )
 
@@ -51,16 +52,43 @@ var (
 	numeric         = flag.Bool("numeric", false, "if true, transforming from a string allows input of numeric values. Default: false")
 	text            = flag.Bool("text", false, "if true, text marshaling methods will be generated. Default: false")
 	output          = flag.String("output", "", "output file name; default srcdir/<type>_enumer.go")
-	transformMethod = flag.String("transform", "noop", "enum item name transformation method. Default: noop")
+	transformMethod = flag.String("transform", "noop", "enum item name transformation method: lower, upper, json, snake, snakeu, kebab, kebabu, dot, space, camel, pascal, title or train. Default: noop")
+	acronyms        = flag.String("acronyms", "", "comma-separated list of acronyms kept as single words when the transform splits names, e.g. HTTP,ID,GraphQL; without acronyms, the snake and kebab transforms split names as they always have. Default: \"\"")
 	trimPrefix      = flag.String("trimprefix", "", "transform each item name by removing a prefix. Default: \"\"")
 	empty           = flag.String("empty", "", "Use an empty string for this enum value. Default: \"\"")
 	lineComment     = flag.Bool("linecomment", false, "use line comment text as printed text when present")
+	protoOutput     = flag.String("proto", "", "if set, a .proto enum definition for each type is written to this file. Default: \"\"")
+	gqlgen          = flag.Bool("gqlgen", false, "if true, GraphQL marshaling methods (MarshalGQL, UnmarshalGQL) will be generated. Default: false")
+	graphqlOutput   = flag.String("graphql", "", "if set, a GraphQL schema enum definition for each type is written to this file. Default: \"\"")
+	jsonSchemaFiles = flag.Bool("jsonschema", false, "if true, a JSON Schema is written to srcdir/<type>_schema.json for each type and JSONSchema methods will be generated. Default: false")
+	openAPIOutput   = flag.String("openapi", "", "if set, OpenAPI 3 schema components for the types are written to this file. Default: \"\"")
+	tsOutput        = flag.String("typescript", "", "if set, TypeScript definitions for the types are written to this file. Default: \"\"")
+	tsEnum          = flag.Bool("tsenum", false, "if true, the TypeScript definitions include a numeric enum mirroring the values. Default: false")
+	cHeaderOutput   = flag.String("cheader", "", "if set, a C header with the constants and a table of their names is written to this file. Default: \"\"")
+	cDefines        = flag.Bool("cdefine", false, "if true, the C header declares the constants with #define instead of an enum. Default: false")
+	fromFile        = flag.String("from", "", "if set, the types and their constants are declared from this YAML or JSON definition file instead of being read from Go code. Default: \"\"")
+	buildTags       = flag.String("tags", "", "comma-separated list of build tags to apply. Default: \"\"")
+	platforms       = flag.String("platforms", "", "comma-separated list of GOOS/GOARCH platforms; if set, the package is loaded for each and per-platform files are written unless the values are identical. Default: \"\"")
+	descriptors     = flag.Bool("descriptors", false, "if true, a Description method and a <Type>Descriptors function listing the values with their doc comments will be generated. Default: false")
+	deprecatedHook  = flag.Bool("deprecatedhook", false, "if true, a <Type>DeprecatedHook variable will be generated, called when FromString decodes a deprecated value. Default: false")
+	registerTypes   = flag.Bool("registry", false, "if true, the types will be registered into the github.com/capsule8/enumer/registry package from init functions. Default: false")
+	generic         = flag.Bool("generic", false, "if true, an EnumDescriptor method will be generated, making the types work with the generic helpers of the github.com/capsule8/enumer/enumer package. Default: false")
+	fromInt         = flag.Bool("fromint", false, "if true, a <Type>FromInt function converting integers with a check will be generated. Default: false")
+	lockFile        = flag.Bool("lock", false, "if true, the values of the constants are checked against and recorded in srcdir/enumer.lock; generation fails if a constant changed value or a new constant took the value of a removed one. Default: false")
+	updateLock      = flag.Bool("update-lock", false, "if true, the values of the constants replace the ones recorded in srcdir/enumer.lock, accepting the changes. Default: false")
+	jsonNames       = flag.String("jsonnames", "", "if set, how JSON names the values instead of String: a transform method, linecomment, or attr:<key> for an enumer:attr attribute. Default: \"\"")
+	sqlNames        = flag.String("sqlnames", "", "if set, how databases name the values instead of String: a transform method, linecomment, or attr:<key> for an enumer:attr attribute. Default: \"\"")
+	decodeAny       = flag.Bool("decodeany", false, "if true, decoding a value accepts its String, JSON and database names alike. Default: false")
+	protoTypeNames  = flag.String("prototype", "", "comma-separated list of protoc-generated Go types (import/path.Type), one per type; if set, ToProto and FromProto conversions will be generated. Default: \"\"")
 )
 
 var comments arrayFlags
+var transformRules arrayFlags
 
 func init() {
 	flag.Var(&comments, "comment", "comments to include in generated code, can repeat. Default: \"\"")
+	flag.Var(&transformRules, "transform-rule", "a regular expression substitution, s/regexp/replacement/, or a text/template with the funcs snake, kebab, upper, lower, trimPrefix and trimSuffix, transforming the item names in place of -transform; can repeat, the rules apply in order. Default: \"\"")
+	flag.VisitAll(func(f *flag.Flag) { enumerFlags.Var(f.Value, f.Name, f.Usage) })
 }
 
 // Usage is a replacement usage function for the flags package.
@@ -68,6 +96,11 @@ func Usage() {
 	fmt.Fprintf(os.Stderr, "Usage of %s:\n", os.Args[0])
 	fmt.Fprintf(os.Stderr, "\tenumer [flags] -type T [directory]\n")
 	fmt.Fprintf(os.Stderr, "\tenumer [flags] -type T files... # Must be a single package\n")
+	fmt.Fprintf(os.Stderr, "\tenumer import-c [flags] -type T -trimprefix PREFIX_ header.h\n")
+	fmt.Fprintf(os.Stderr, "\tenumer import-proto [flags] [-type T] file.proto\n")
+	fmt.Fprintf(os.Stderr, "\tenumer [flags] -from defs.yaml [directory]\n")
+	fmt.Fprintf(os.Stderr, "\tenumer stale [packages] # or go vet -vettool=$(which enumer)\n")
+	fmt.Fprintf(os.Stderr, "\tenumer diff old-dir new-dir # Prints a JSON line per incompatible change\n")
 	fmt.Fprintf(os.Stderr, "For more information, see:\n")
 	fmt.Fprintf(os.Stderr, "\thttps://github.com/alvaroloes/enumer\n")
 	fmt.Fprintf(os.Stderr, "Flags:\n")
@@ -78,12 +111,45 @@ func main() {
 	log.SetFlags(0)
 	log.SetPrefix("enumer: ")
 	flag.Usage = Usage
-	flag.Parse()
-	if len(*typeNames) == 0 {
+	// "enumer stale" reports generated files that are out of date, and so
+	// does enumer when go vet runs it as its -vettool.
+	if len(os.Args) > 1 && (os.Args[1] == "stale" || isVetTool(os.Args[1:])) {
+		runStaleChecker()
+		return
+	}
+	// "enumer diff" reports the incompatible changes between two versions of
+	// a package.
+	if len(os.Args) > 1 && os.Args[1] == "diff" {
+		runDiff(os.Args[2:])
+		return
+	}
+	// "enumer import-c" and "enumer import-proto" declare the types from a
+	// C header or a .proto file instead of finding them in a Go package.
+	var mode string
+	if len(os.Args) > 1 && (os.Args[1] == "import-c" || os.Args[1] == "import-proto") {
+		mode = os.Args[1]
+		flag.CommandLine.Parse(os.Args[2:])
+	} else {
+		flag.Parse()
+	}
+	if len(*typeNames) == 0 && len(*fromFile) == 0 && mode != "import-proto" {
 		flag.Usage()
 		os.Exit(2)
 	}
-	types := strings.Split(*typeNames, ",")
+	if mode == "import-proto" && flag.NArg() != 1 {
+		log.Fatalf("import-proto takes a single .proto file")
+	}
+	var types []string
+	switch {
+	case len(*typeNames) > 0:
+		types = strings.Split(*typeNames, ",")
+	case mode == "import-proto":
+		// Default: generate every enum of the .proto file.
+		types = protoEnumNames(flag.Arg(0))
+	default:
+		// Default: generate every type of the definition file.
+		types = definitionTypeNames(*fromFile)
+	}
 
 	// We accept either one directory or a list of files. Which do we have?
 	args := flag.Args()
@@ -92,22 +158,218 @@ func main() {
 		args = []string{"."}
 	}
 
-	// Parse the package once.
-	var (
-		dir string
-		g   Generator
-	)
-
+	var dir string
 	if len(args) == 1 && isDirectory(args[0]) {
 		dir = args[0]
 	} else {
 		dir = filepath.Dir(args[0])
 	}
 
-	g.parsePackage(args)
+	var tags []string
+	if len(*buildTags) > 0 {
+		tags = strings.Split(*buildTags, ",")
+	}
+
+	// Figure out filename to write to
+	outputName := *output
+	if outputName == "" {
+		baseName := fmt.Sprintf("%s_string.go", types[0])
+		outputName = filepath.Join(dir, strings.ToLower(baseName))
+	}
+
+	if len(*platforms) == 0 {
+		// Parse the package once.
+		var g Generator
+		src := g.generateFile(args, types, dir, mode, tags, "")
+		if g.lock != nil {
+			g.updateLockFile(dir, *updateLock)
+		}
+		writeOutput(outputName, types[0], src)
+		removeStaleOutputs(outputName, []string{outputName})
+		g.writeSideOutputs(types, dir)
+		return
+	}
+
+	if mode != "" || len(*fromFile) > 0 {
+		log.Fatalf("-platforms can only be used with Go packages")
+	}
+	if *lockFile || *updateLock {
+		log.Fatalf("-lock cannot be used with -platforms")
+	}
+	// Parse the package once per platform, as the values may differ.
+	targets := strings.Split(*platforms, ",")
+	gens := make([]Generator, len(targets))
+	srcs := make([][]byte, len(targets))
+	constraints := make([]string, len(targets))
+	shared := true
+	for i, platform := range targets {
+		osArch := strings.Split(platform, "/")
+		if len(osArch) != 2 || osArch[0] == "" || osArch[1] == "" {
+			log.Fatalf("invalid platform %q: want GOOS/GOARCH", platform)
+		}
+		srcs[i] = gens[i].generateFile(args, types, dir, "", tags, platform)
+		constraints[i] = osArch[0] + " && " + osArch[1]
+		shared = shared && bytes.Equal(srcs[i], srcs[0])
+	}
+	if shared {
+		constraint := strings.Join(constraints, " || ")
+		if len(targets) > 1 {
+			constraint = "(" + strings.Join(constraints, ") || (") + ")"
+		}
+		writeOutput(outputName, types[0], withBuildConstraint(srcs[0], constraint))
+		removeStaleOutputs(outputName, []string{outputName})
+		gens[0].writeSideOutputs(types, dir)
+		return
+	}
+	if gens[0].hasSideOutputs() {
+		log.Fatalf("the values of %s differ between platforms; only Go code can be generated per platform", strings.Join(types, ", "))
+	}
+	base := strings.TrimSuffix(outputName, ".go")
+	written := make([]string, len(targets))
+	for i, platform := range targets {
+		written[i] = fmt.Sprintf("%s_%s.go", base, strings.Replace(platform, "/", "_", 1))
+		writeOutput(written[i], types[0], withBuildConstraint(srcs[i], constraints[i]))
+	}
+	removeStaleOutputs(outputName, written)
+}
+
+// removeStaleOutputs removes the outputs of earlier runs with -platforms that
+// this run did not write: the shared output and the per-platform ones next to
+// it, e.g. day_string.go and day_string_linux_amd64.go. Files not generated by
+// enumer with -platforms are left alone.
+func removeStaleOutputs(outputName string, written []string) {
+	candidates, err := filepath.Glob(strings.TrimSuffix(outputName, ".go") + "_*_*.go")
+	if err != nil {
+		log.Fatalf("looking for stale outputs: %s", err)
+	}
+	keep := make(map[string]bool)
+	for _, name := range written {
+		keep[filepath.Clean(name)] = true
+	}
+	for _, name := range append(candidates, outputName) {
+		if keep[filepath.Clean(name)] || !generatedForPlatforms(name) {
+			continue
+		}
+		if err := os.Remove(name); err != nil {
+			log.Fatalf("removing stale output: %s", err)
+		}
+	}
+}
+
+// generatedForPlatforms reports whether the named file was generated by
+// enumer with -platforms.
+func generatedForPlatforms(fileName string) bool {
+	file, err := parser.ParseFile(token.NewFileSet(), fileName, nil, parser.PackageClauseOnly|parser.ParseComments)
+	if err != nil {
+		return false
+	}
+	args, ok := generatedCommand(file)
+	if !ok {
+		return false
+	}
+	for _, arg := range args {
+		if flagName := strings.SplitN(strings.TrimLeft(arg, "-"), "=", 2)[0]; strings.HasPrefix(arg, "-") && flagName == "platforms" {
+			return true
+		}
+	}
+	return false
+}
+
+// withBuildConstraint adds a //go:build line to the generated source.
+func withBuildConstraint(src []byte, constraint string) []byte {
+	return append([]byte("//go:build "+constraint+"\n\n"), src...)
+}
+
+// generateFile loads the package, or imports the C header or .proto file
+// according to mode, for the platform and returns the formatted source of the
+// generated file. An empty platform stands for the host.
+func (g *Generator) generateFile(args, types []string, dir string, mode string, tags []string, platform string) []byte {
+	// Types declared by the generator go to the package of the output.
+	pkgDir := dir
+	if *output != "" {
+		pkgDir = filepath.Dir(*output)
+	}
+	prefix := *trimPrefix
+	switch {
+	case mode == "import-c":
+		if len(args) != 1 || len(types) != 1 {
+			log.Fatalf("import-c takes a single type and a single header file")
+		}
+		g.pkg = &Package{name: packageName(pkgDir)}
+		g.importCHeader(args[0], types[0], prefix)
+		// The prefix was trimmed from the C names already.
+		prefix = ""
+	case mode == "import-proto":
+		g.pkg = &Package{name: packageName(pkgDir)}
+		g.importProto(args[0], types)
+	case len(*fromFile) > 0:
+		g.pkg = &Package{name: packageName(pkgDir)}
+		g.defineFromFile(*fromFile, types)
+	default:
+		g.parsePackage(args, tags, platform)
+	}
+	return g.generateSource(types, dir, pkgDir, prefix, commandLine(os.Args[1:]))
+}
+
+// generateSource returns the formatted source of the generated file for the
+// package loaded already, recording command as the arguments of enumer in the
+// header.
+func (g *Generator) generateSource(types []string, dir, pkgDir, prefix, command string) []byte {
+	if len(*protoTypeNames) > 0 {
+		protoTypes := strings.Split(*protoTypeNames, ",")
+		if len(protoTypes) != len(types) {
+			fatalf("-prototype lists %d types, -type lists %d", len(protoTypes), len(types))
+		}
+		g.protoTypes = make(map[string]*protoType)
+		for i, spec := range protoTypes {
+			g.protoTypes[types[i]] = loadProtoType(dir, spec)
+		}
+	}
+	g.protoEnums = *protoOutput != ""
+	g.gqlgen = *gqlgen
+	g.graphqlEnums = *graphqlOutput != ""
+	g.jsonSchemas = *jsonSchemaFiles
+	g.openAPI = *openAPIOutput != ""
+	g.typeScript = *tsOutput != ""
+	g.tsEnums = *tsEnum
+	g.cHeaders = *cHeaderOutput != ""
+	g.cDefines = *cDefines
+	g.descriptors = *descriptors
+	g.deprecatedHook = *deprecatedHook
+	g.registry = *registerTypes
+	g.generic = *generic
+	g.fromInt = *fromInt
+	if *acronyms != "" {
+		g.acronyms = strings.Split(*acronyms, ",")
+	}
+	if len(transformRules) > 0 && *transformMethod != "noop" {
+		fatalf("-transform-rule cannot be used with -transform")
+	}
+	g.jsonNames = *jsonNames
+	g.sqlNames = *sqlNames
+	g.decodeAny = *decodeAny
+	if err := checkNameSetSpec(g.jsonNames); err != nil {
+		fatalf("-jsonnames: %s", err)
+	}
+	if err := checkNameSetSpec(g.sqlNames); err != nil {
+		fatalf("-sqlnames: %s", err)
+	}
+	for _, rule := range transformRules {
+		r, err := parseNameRule(rule, g.acronyms)
+		if err != nil {
+			fatalf("-transform-rule: %s", err)
+		}
+		g.nameRules = append(g.nameRules, r)
+	}
+	if *lockFile || *updateLock {
+		g.lock = make(lockedValues)
+	}
+	if g.registry && g.pkg.path == "" {
+		g.pkg.path = packagePath(pkgDir, g.pkg.name)
+	}
 
 	// Print the header and package clause.
-	g.Printf("// Code generated by \"enumer %s\"; DO NOT EDIT.\n", strings.Join(os.Args[1:], " "))
+	g.Printf("// Code generated by \"enumer %s\"; DO NOT EDIT.\n", command)
 	g.Printf("\n")
 	if comments.String() != "" {
 		g.Printf("// %s\n", comments.String())
@@ -116,7 +378,7 @@ func main() {
 	g.Printf("\n")
 	g.Printf("import (\n")
 	g.Printf("\t\"fmt\"\n")
-	if *numeric {
+	if *numeric || *gqlgen {
 		g.Printf("\t\"strconv\"\n")
 	}
 	if *ignoreCase || g.transformRequiresStrings(*transformMethod) {
@@ -125,29 +387,95 @@ func main() {
 	if *sql {
 		g.Printf("\t\"database/sql/driver\"\n")
 	}
-	if *json {
+	if *json || *jsonSchemaFiles {
 		g.Printf("\t\"encoding/json\"\n")
 	}
+	if *gqlgen {
+		g.Printf("\t\"io\"\n")
+	}
+	imported := make(map[string]bool)
+	for _, typeName := range types {
+		if pt, ok := g.protoTypes[typeName]; ok && !imported[pt.path] {
+			g.Printf("\t%s %q\n", pt.pkg, pt.path)
+			imported[pt.path] = true
+		}
+	}
+	if g.registry {
+		g.Printf("\t%q\n", registryPath)
+	}
+	if g.generic {
+		g.Printf("\t%q\n", enumerPath)
+	}
+	for _, typeName := range types {
+		if g.typeReserved(typeName) != nil {
+			g.Printf("\t\"errors\"\n")
+			break
+		}
+	}
 	g.Printf(")\n")
 
 	// Run generate for each type.
 	for _, typeName := range types {
-		g.generate(typeName, *json, *yaml, *sql, *text, *transformMethod, *trimPrefix, *lineComment, *ignoreCase, *numeric, *empty)
+		g.generate(typeName, *json, *yaml, *sql, *text, *transformMethod, prefix, *lineComment, *ignoreCase, *numeric, *empty)
 	}
 
 	// Format the output.
-	src := g.format()
+	return g.format()
+}
 
-	// Figure out filename to write to
-	outputName := *output
-	if outputName == "" {
-		baseName := fmt.Sprintf("%s_string.go", types[0])
-		outputName = filepath.Join(dir, strings.ToLower(baseName))
+// hasSideOutputs reports whether files other than Go code were requested.
+func (g *Generator) hasSideOutputs() bool {
+	return g.protoEnums || g.graphqlEnums || g.jsonSchemas || g.openAPI || g.typeScript || g.cHeaders
+}
+
+// writeSideOutputs writes the requested files other than Go code.
+func (g *Generator) writeSideOutputs(types []string, dir string) {
+	if g.protoEnums {
+		var proto bytes.Buffer
+		fmt.Fprintf(&proto, "// Code generated by \"enumer %s\"; DO NOT EDIT.\n\n", commandLine(os.Args[1:]))
+		fmt.Fprintf(&proto, "syntax = \"proto3\";\n\n")
+		fmt.Fprintf(&proto, "package %s;\n", g.pkg.name)
+		proto.Write(g.protoBuf.Bytes())
+		writeOutput(*protoOutput, types[0], proto.Bytes())
+	}
+	if g.graphqlEnums {
+		var schema bytes.Buffer
+		fmt.Fprintf(&schema, "# Code generated by \"enumer %s\"; DO NOT EDIT.\n", commandLine(os.Args[1:]))
+		schema.Write(g.graphqlBuf.Bytes())
+		writeOutput(*graphqlOutput, types[0], schema.Bytes())
+	}
+	if g.jsonSchemas {
+		for _, s := range g.schemas {
+			schemaName := filepath.Join(dir, strings.ToLower(s.Title)+"_schema.json")
+			writeOutput(schemaName, s.Title, s.document())
+		}
+	}
+	if g.openAPI {
+		writeOutput(*openAPIOutput, types[0], openAPIComponents(g.schemas))
+	}
+	if g.typeScript {
+		var ts bytes.Buffer
+		fmt.Fprintf(&ts, "// Code generated by \"enumer %s\"; DO NOT EDIT.\n", commandLine(os.Args[1:]))
+		ts.Write(g.tsBuf.Bytes())
+		writeOutput(*tsOutput, types[0], ts.Bytes())
 	}
+	if g.cHeaders {
+		header := fmt.Sprintf("/* Code generated by \"enumer %s\"; DO NOT EDIT. */\n", commandLine(os.Args[1:]))
+		writeOutput(*cHeaderOutput, types[0], g.cHeader(*cHeaderOutput, header))
+	}
+}
+
+// fatalf reports an error of the generation and exits. The stale analyzer,
+// which runs the generator in the process of go vet, replaces it to report
+// the error instead.
+var fatalf = log.Fatalf
 
+// writeOutput writes src to the named file, going through a temporary file
+// so a failed run never leaves a truncated output behind.
+func writeOutput(outputName, typeName string, src []byte) {
 	// Write to tmpfile first
-	tmpName := fmt.Sprintf("%s_enumer_", filepath.Base(types[0]))
-	tmpFile, err := ioutil.TempFile(filepath.Dir(types[0]), tmpName)
+	tmpName := fmt.Sprintf("%s_enumer_", filepath.Base(typeName))
+	tmpFile, err := ioutil.TempFile(filepath.Dir(typeName), tmpName)
 	if err != nil {
 		log.Fatalf("creating temporary file for output: %s", err)
 	}
@@ -176,11 +504,127 @@ func isDirectory(name string) bool {
 	return info.IsDir()
 }
 
+// packageName returns the name of the Go package in the directory, or a name
+// derived from the directory if it holds no Go files yet.
+func packageName(dir string) string {
+	fset := token.NewFileSet()
+	files, _ := filepath.Glob(filepath.Join(dir, "*.go"))
+	for _, file := range files {
+		if strings.HasSuffix(file, "_test.go") {
+			continue
+		}
+		f, err := parser.ParseFile(fset, file, nil, parser.PackageClauseOnly)
+		if err == nil {
+			return f.Name.Name
+		}
+	}
+	abs, err := filepath.Abs(dir)
+	if err != nil {
+		log.Fatal(err)
+	}
+	name := strings.Map(func(r rune) rune {
+		if r == '_' || unicode.IsLetter(r) || unicode.IsDigit(r) {
+			return unicode.ToLower(r)
+		}
+		return -1
+	}, filepath.Base(abs))
+	if name == "" || !unicode.IsLetter(rune(name[0])) {
+		name = "enums" + name
+	}
+	return name
+}
+
 // Generator holds the state of the analysis. Primarily used to buffer
 // the output for format.Source.
 type Generator struct {
 	buf bytes.Buffer // Accumulated output.
 	pkg *Package     // Package we are scanning.
+
+	protoEnums bool                  // Whether to accumulate .proto enum definitions.
+	protoBuf   bytes.Buffer          // Accumulated .proto enum definitions.
+	protoTypes map[string]*protoType // Protoc-generated counterparts, by type name.
+
+	gqlgen       bool         // Whether to generate the GraphQL marshaling methods.
+	graphqlEnums bool         // Whether to accumulate GraphQL schema enum definitions.
+	graphqlBuf   bytes.Buffer // Accumulated GraphQL schema enum definitions.
+
+	jsonSchemas bool          // Whether to generate JSONSchema methods and write JSON Schemas.
+	openAPI     bool          // Whether to write OpenAPI schema components.
+	schemas     []*jsonSchema // Accumulated JSON Schemas, one per type.
+
+	typeScript bool         // Whether to accumulate TypeScript definitions.
+	tsEnums    bool         // Whether the TypeScript definitions include numeric enums.
+	tsBuf      bytes.Buffer // Accumulated TypeScript definitions.
+
+	cHeaders bool         // Whether to accumulate C header definitions.
+	cDefines bool         // Whether C constants are #defines rather than enums.
+	cBuf     bytes.Buffer // Accumulated C header definitions.
+
+	descriptors    bool // Whether to generate the Description method and descriptors.
+	deprecatedHook bool // Whether to generate the hook called on decoding deprecated values.
+	registry       bool // Whether to register the types into the runtime registry.
+	generic        bool // Whether to generate the EnumDescriptor method.
+	fromInt        bool // Whether to generate the checked FromInt conversion.
+
+	lock lockedValues // Values of the constants to check against enumer.lock; nil unless -lock is set.
+
+	acronyms  []string   // Words kept whole when splitting names for the transforms.
+	nameRules []nameRule // User-defined transformation of the names, in place of the transform method.
+
+	jsonNames string     // How to name the values in JSON, if not as String does.
+	sqlNames  string     // How to name the values in databases, if not as String does.
+	decodeAny bool       // Whether the decoding accepts the names of every set.
+	nameSets  []*nameSet // The name sets of the type being generated.
+
+	reserved   *reservedSet // The numbers and names reserved on the type being generated, or nil.
+	decodeTail string       // The checks of FromString after the lookup of the names of the type being generated.
+
+	defined map[string]*definedType // Types declared by the generator itself, by name.
+}
+
+// definedType is a type whose declaration is generated along with its
+// methods, from a source other than Go code.
+type definedType struct {
+	underlying string       // Underlying integer type.
+	doc        string       // Doc comment of the type.
+	values     []Value      // The constants of the type, in order of declaration.
+	reserved   *reservedSet // The numbers and names reserved on the type, or nil.
+}
+
+// define registers a type to be declared by the generator.
+func (g *Generator) define(typeName string, def *definedType) {
+	if g.defined == nil {
+		g.defined = make(map[string]*definedType)
+	}
+	g.defined[typeName] = def
+}
+
+// declareType prints the declaration of a defined type and its constants.
+func (g *Generator) declareType(typeName string, def *definedType) {
+	g.Printf("\n")
+	printDoc := func(indent, doc string) {
+		if doc == "" {
+			return
+		}
+		for _, line := range strings.Split(doc, "\n") {
+			g.Printf("%s// %s\n", indent, line)
+		}
+	}
+	printDoc("", def.doc)
+	if def.reserved != nil {
+		g.Printf("%s\n", def.reserved.directive())
+	}
+	g.Printf("type %s %s\n\n", typeName, def.underlying)
+	g.Printf("const (\n")
+	for _, value := range def.values {
+		printDoc("\t", value.doc)
+		g.Printf("\t%s %s = %s", value.constName, typeName, value.str)
+		if value.comment != "" {
+			g.Printf(" // %s", value.comment)
+		}
+		g.Printf("\n")
+	}
+	g.Printf(")\n")
 }
 
 // Printf prints the string to the output
@@ -201,6 +645,8 @@ type File struct {
 type Package struct {
 	dir      string
 	name     string
+	path     string
+	fset     *token.FileSet
 	defs     map[*ast.Ident]types.Object
 	files    []*File
 	typesPkg *types.Package
@@ -272,14 +718,20 @@ type Package struct {
 //	g.pkg.check(fs, astFiles)
 //}
 
-// parsePackage analyzes the single package constructed from the patterns and tags.
+// parsePackage analyzes the single package constructed from the patterns and tags,
+// as it is built for the platform ("GOOS/GOARCH", or "" for the host).
 // parsePackage exits if there is an error.
-func (g *Generator) parsePackage(patterns []string) {
+func (g *Generator) parsePackage(patterns []string, tags []string, platform string) {
 	cfg := &packages.Config{
 		Mode: packages.LoadSyntax,
 		// TODO: Need to think about constants in test files. Maybe write type_string_test.go
 		// in a separate pass? For later.
-		Tests: false,
+		Tests:      false,
+		BuildFlags: []string{fmt.Sprintf("-tags=%s", strings.Join(tags, " "))},
+	}
+	if platform != "" {
+		osArch := strings.SplitN(platform, "/", 2)
+		cfg.Env = append(os.Environ(), "GOOS="+osArch[0], "GOARCH="+osArch[1])
 	}
 	pkgs, err := packages.Load(cfg, patterns...)
 	if err != nil {
@@ -295,6 +747,8 @@ func (g *Generator) parsePackage(patterns []string) {
 func (g *Generator) addPackage(pkg *packages.Package) {
 	g.pkg = &Package{
 		name:  pkg.Name,
+		path:  pkg.PkgPath,
+		fset:  pkg.Fset,
 		defs:  pkg.TypesInfo.Defs,
 		files: make([]*File, len(pkg.Syntax)),
 	}
@@ -334,9 +788,22 @@ func (g *Generator) transformRequiresStrings(transformMethod string) bool {
 }
 
 func (g *Generator) transformValueNames(values []Value, transformMethod string, empty string) {
-	var sep rune
+	if len(g.nameRules) > 0 {
+		if err := applyNameRules(values, g.nameRules, empty); err != nil {
+			fatalf("-transform-rule: %s", err)
+		}
+		return
+	}
+	g.applyTransform(values, transformMethod, empty)
+}
+
+// applyTransform names the values by the transform method.
+func (g *Generator) applyTransform(values []Value, transformMethod string, empty string) {
+	var sep string
 	var upper bool
 	var json bool
+	// For the mixed case transforms, whether to capitalize the ith word.
+	var capitalized func(i int) bool
 	switch transformMethod {
 	case "lower":
 		upper = false
@@ -345,16 +812,36 @@ func (g *Generator) transformValueNames(values []Value, transformMethod string,
 	case "json":
 		json = true
 	case "snake":
-		sep = '_'
+		sep = "_"
 	case "snakeu":
-		sep = '_'
+		sep = "_"
 		upper = true
 	case "kebab":
-		sep = '-'
+		sep = "-"
 	case "kebabu":
-		sep = '-'
+		sep = "-"
 		upper = true
+	case "dot":
+		sep = "."
+	case "space":
+		sep = " "
+	case "camel":
+		capitalized = func(i int) bool { return i > 0 }
+	case "pascal":
+		capitalized = func(int) bool { return true }
+	case "title":
+		sep = " "
+		capitalized = func(int) bool { return true }
+	case "train":
+		sep = "-"
+		capitalized = func(int) bool { return true }
 	default:
+		// The names are kept, but for the one named empty.
+		for i := range values {
+			if values[i].name == empty {
+				values[i].name = ""
+			}
+		}
 		return
 	}
 
@@ -366,9 +853,19 @@ func (g *Generator) transformValueNames(values []Value, transformMethod string,
 			} else {
 				values[i].name = strings.ToLower(s)
 			}
+		} else if capitalized != nil {
+			words := nameWords(s, g.acronyms)
+			for j, word := range words {
+				if capitalized(j) {
+					words[j] = capitalize(word, g.acronyms)
+				} else {
+					words[j] = strings.ToLower(word)
+				}
+			}
+			values[i].name = strings.Join(words, sep)
 		} else {
-			if sep != 0 {
-				s = name.Delimit(s, sep)
+			if sep != "" {
+				s = delimit(s, rune(sep[0]), g.acronyms)
 			}
 			if upper {
 				values[i].name = strings.ToUpper(s)
@@ -400,6 +897,10 @@ func (g *Generator) replaceValuesWithLineComment(values []Value) {
 // generate produces the String method for the named type.
 func (g *Generator) generate(typeName string, includeJSON, includeYAML, includeSQL, includeText bool, transformMethod string, trimPrefix string, lineComment bool, ignoreCase bool, numeric bool, empty string) {
 	values := make([]Value, 0, 100)
+	if def, ok := g.defined[typeName]; ok {
+		g.declareType(typeName, def)
+		values = append(values, def.values...)
+	}
 	for _, file := range g.pkg.files {
 		// Set the state for this run of the walker.
 		file.typeName = typeName
@@ -411,9 +912,33 @@ func (g *Generator) generate(typeName string, includeJSON, includeYAML, includeS
 	}
 
 	if len(values) == 0 {
-		log.Fatalf("no values defined for type %s", typeName)
+		fatalf("no values defined for type %s", typeName)
+	}
+	caseMatch := CaseMatch(CaseNone)
+	if ignoreCase {
+		switch transformMethod {
+		case "upper", "snakeu", "kebabu":
+			caseMatch = CaseUpper
+		case "lower", "snake", "kebab":
+			caseMatch = CaseLower
+		default:
+			caseMatch = CaseMixed
+		}
+	}
+	g.nameSets = nil
+	if g.jsonNames != "" {
+		g.nameSets = append(g.nameSets, g.newNameSet(typeName, "JSON", g.jsonNames, values, trimPrefix, caseMatch))
+	}
+	if g.sqlNames != "" {
+		g.nameSets = append(g.nameSets, g.newNameSet(typeName, "SQL", g.sqlNames, values, trimPrefix, caseMatch))
+	}
+	if g.lock != nil {
+		g.lockValues(typeName, values)
 	}
 
+	if trimPrefix != "" && !anyHasPrefix(values, trimPrefix) {
+		log.Printf("warning: -trimprefix %q matches no constant of %s", trimPrefix, typeName)
+	}
 	g.trimValueNames(values, trimPrefix)
 
 	g.transformValueNames(values, transformMethod, empty)
@@ -421,6 +946,34 @@ func (g *Generator) generate(typeName string, includeJSON, includeYAML, includeS
 	if lineComment {
 		g.replaceValuesWithLineComment(values)
 	}
+	if empty != "" && !anyEmpty(values) {
+		log.Printf("warning: -empty %q matches no name of %s", empty, typeName)
+	}
+	if err := checkNameCollisions(values, caseMatch); err != nil {
+		fatalf("names of %s collide:%s", typeName, err)
+	}
+	if g.decodeAny && len(g.nameSets) > 0 {
+		// Every name decodes every value, so no two values may share one.
+		all := append([]Value(nil), values...)
+		for _, set := range g.nameSets {
+			for _, v := range values {
+				v.name = set.names[v.value]
+				all = append(all, v)
+			}
+		}
+		match := CaseMatch(CaseNone)
+		if caseMatch != CaseNone {
+			match = CaseLower
+		}
+		if err := checkNameCollisions(all, match); err != nil {
+			fatalf("names of %s collide under -decodeany:%s", typeName, err)
+		}
+	}
+	g.reserved = g.typeReserved(typeName)
+	if g.reserved != nil {
+		g.decodeReserved(g.reserved, trimPrefix, transformMethod)
+		g.reserved.check(typeName, values, g.nameSets)
+	}
 
 	runs := splitIntoRuns(values)
 	const runsThreshold = 1
@@ -429,16 +982,19 @@ func (g *Generator) generate(typeName string, includeJSON, includeYAML, includeS
 		g.buildMap(runs, typeName, true)
 	}
 
-	if ignoreCase {
-		if transformMethod == "upper" || transformMethod == "snakeu" || transformMethod == "kebabu" {
-			g.buildBasicExtras(runs, typeName, runsThreshold, CaseUpper, numeric)
-		} else if transformMethod == "lower" || transformMethod == "snake" || transformMethod == "kebab" {
-			g.buildBasicExtras(runs, typeName, runsThreshold, CaseLower, numeric)
-		} else {
-			g.buildBasicExtras(runs, typeName, runsThreshold, CaseMixed, numeric)
-		}
-	} else {
-		g.buildBasicExtras(runs, typeName, runsThreshold, CaseNone, numeric)
+	g.buildBasicExtras(runs, typeName, runsThreshold, caseMatch, numeric)
+	g.buildAttrMethods(runs, typeName, g.typeAttrs(typeName))
+	for _, set := range g.nameSets {
+		g.buildNameSet(runs, typeName, set, caseMatch)
+	}
+	if g.descriptors {
+		g.buildDescriptors(runs, typeName)
+	}
+	if g.registry {
+		g.buildRegistration(typeName)
+	}
+	if g.generic {
+		g.buildEnumDescriptor(typeName)
 	}
 
 	if includeJSON {
@@ -453,6 +1009,37 @@ func (g *Generator) generate(typeName string, includeJSON, includeYAML, includeS
 	if includeSQL {
 		g.addValueAndScanMethod(typeName)
 	}
+	if pt, ok := g.protoTypes[typeName]; ok {
+		g.buildProtoMethods(runs, typeName, trimPrefix, pt)
+	}
+	if g.protoEnums {
+		g.buildProtoEnum(runs, typeName, trimPrefix)
+	}
+	if g.gqlgen {
+		g.buildGQLMethods(runs, typeName)
+	}
+	if g.graphqlEnums {
+		g.buildGraphQLEnum(runs, typeName)
+	}
+	if g.jsonSchemas || g.openAPI {
+		// encoding/json falls back to MarshalText when there is no MarshalJSON,
+		// which uses the names of String.
+		var names *nameSet
+		if includeJSON {
+			names = g.nameSetFor("JSON")
+		}
+		schema := g.newJSONSchema(runs, typeName, includeJSON || includeText, names)
+		g.schemas = append(g.schemas, schema)
+		if g.jsonSchemas {
+			g.buildJSONSchemaMethod(typeName, schema)
+		}
+	}
+	if g.typeScript {
+		g.buildTypeScript(runs, typeName, g.tsEnums)
+	}
+	if g.cHeaders {
+		g.buildCHeader(runs, typeName, trimPrefix, g.cDefines)
+	}
 }
 
 // splitIntoRuns breaks the values into runs of contiguous sequences.
@@ -471,6 +1058,9 @@ func splitIntoRuns(values []Value) [][]Value {
 		if values[i].value != values[i-1].value {
 			values[j] = values[i]
 			j++
+		} else {
+			values[j-1].aliases = append(values[j-1].aliases, values[i].constName)
+			values[j-1].liveAlias = values[j-1].liveAlias || !values[i].deprecated()
 		}
 	}
 	values = values[:j]
@@ -502,16 +1092,24 @@ func (g *Generator) format() []byte {
 
 // Value represents a declared constant.
 type Value struct {
-	name string // The name of the constant after transformation (i.e. camel case => snake case)
+	constName string // The name of the constant as declared.
+	name      string // The name of the constant after transformation (i.e. camel case => snake case)
 	// The value is stored as a bit pattern alone. The boolean tells us
 	// whether to interpret it as an int64 or a uint64; the only place
 	// this matters is when sorting.
 	// Much of the time the str field is all we need; it is printed
 	// by Value.String.
-	value   uint64 // Will be converted to int64 when needed.
-	signed  bool   // Whether the constant is a signed type.
-	str     string // The string representation given by the "go/exact" package.
-	comment string // The comment on the right of the constant
+	value   uint64          // Will be converted to int64 when needed.
+	signed  bool            // Whether the constant is a signed type.
+	str     string          // The string representation given by the "go/exact" package.
+	comment string          // The comment on the right of the constant
+	doc     string          // The doc comment above the constant
+	attrs   map[string]attr // The attributes set by enumer:attr comments
+	aliases []string        // The other constants with the same value, set by splitIntoRuns
+	pos     token.Position  // The declaration of the constant, if it is in Go code
+
+	liveAlias bool              // Whether one of the aliases is not deprecated, set by splitIntoRuns
+	setNames  map[string]string // The names in the name sets by format, set for "enumer diff" only
 }
 
 func (v *Value) String() string {
@@ -578,20 +1176,20 @@ func (f *File) genDecl(node ast.Node) bool {
 			// types.Const, and extract its value.
 			obj, ok := f.pkg.defs[name]
 			if !ok {
-				log.Fatalf("no value for constant %s", name)
+				fatalf("no value for constant %s", name)
 			}
 			info := obj.Type().Underlying().(*types.Basic).Info()
 			if info&types.IsInteger == 0 {
-				log.Fatalf("can't handle non-integer constant type %s", typ)
+				fatalf("can't handle non-integer constant type %s", typ)
 			}
 			value := obj.(*types.Const).Val() // Guaranteed to succeed as this is CONST.
 			if value.Kind() != exact.Int {
-				log.Fatalf("can't happen: constant is not an integer %s", name)
+				fatalf("can't happen: constant is not an integer %s", name)
 			}
 			i64, isInt := exact.Int64Val(value)
 			u64, isUint := exact.Uint64Val(value)
 			if !isInt && !isUint {
-				log.Fatalf("internal error: value of %s is not an integer: %s", name, value.String())
+				fatalf("internal error: value of %s is not an integer: %s", name, value.String())
 			}
 			if !isInt {
 				u64 = uint64(i64)
@@ -600,13 +1198,29 @@ func (f *File) genDecl(node ast.Node) bool {
 			if c := vspec.Comment; c != nil && len(c.List) == 1 {
 				comment = strings.TrimSpace(c.Text())
 			}
+			doc := vspec.Doc
+			if doc == nil && !decl.Lparen.IsValid() {
+				// "// Doc\nconst X T = 1". The doc belongs to the declaration.
+				doc = decl.Doc
+			}
+
+			attrs, err := parseAttrs(doc, vspec.Comment)
+			if err != nil {
+				fatalf("constant %s: %s", name, err)
+			}
 
 			v := Value{
-				name:    name.Name,
-				value:   u64,
-				signed:  info&types.IsUnsigned == 0,
-				str:     value.String(),
-				comment: comment,
+				constName: name.Name,
+				name:      name.Name,
+				value:     u64,
+				signed:    info&types.IsUnsigned == 0,
+				str:       value.String(),
+				comment:   comment,
+				doc:       strings.TrimSpace(doc.Text()),
+				attrs:     attrs,
+			}
+			if f.pkg.fset != nil {
+				v.pos = f.pkg.fset.Position(name.Pos())
 			}
 			f.values = append(f.values, v)
 		}
@@ -614,6 +1228,38 @@ func (f *File) genDecl(node ast.Node) bool {
 	return false
 }
 
+// typeDoc returns the doc comment of the named type, if any.
+func (g *Generator) typeDoc(typeName string) string {
+	if def, ok := g.defined[typeName]; ok {
+		return def.doc
+	}
+	return strings.TrimSpace(g.typeDocGroup(typeName).Text())
+}
+
+// typeDocGroup returns the comments above the declaration of the named type
+// in the package, if any.
+func (g *Generator) typeDocGroup(typeName string) *ast.CommentGroup {
+	for _, file := range g.pkg.files {
+		for _, decl := range file.file.Decls {
+			decl, ok := decl.(*ast.GenDecl)
+			if !ok || decl.Tok != token.TYPE {
+				continue
+			}
+			for _, spec := range decl.Specs {
+				tspec := spec.(*ast.TypeSpec) // Guaranteed to succeed as this is TYPE.
+				if tspec.Name.Name != typeName {
+					continue
+				}
+				if tspec.Doc == nil && !decl.Lparen.IsValid() {
+					return decl.Doc
+				}
+				return tspec.Doc
+			}
+		}
+	}
+	return nil
+}
+
 // Helpers
 
 // usize returns the number of bits of the smallest unsigned integer
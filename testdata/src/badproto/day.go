@@ -0,0 +1,11 @@
+package badproto
+
+//go:generate enumer -type Day -prototype nodot
+
+// Day is a day of the week.
+type Day int
+
+const (
+	DayUnspecified Day = iota
+	DayMonday
+)
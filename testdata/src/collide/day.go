@@ -0,0 +1,14 @@
+package collide
+
+//go:generate enumer -type Day -trimprefix Day
+
+// Day is a day of the week.
+type Day int
+
+const (
+	DayMonday Day = iota
+	DayTuesday
+)
+
+// Monday was added after generating, and prints as DayMonday does.
+const Monday Day = 2
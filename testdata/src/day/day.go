@@ -0,0 +1,14 @@
+package day
+
+//go:generate enumer -type Day -trimprefix Day
+
+// Day is a day of the week.
+type Day int
+
+const (
+	DayMonday Day = iota
+	DayTuesday
+)
+
+// DayWednesday was added after generating.
+const DayWednesday Day = 2
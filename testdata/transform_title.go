@@ -0,0 +1,24 @@
+package main
+
+import "fmt"
+
+type CamelCaseValue int
+
+const (
+	DayOfWeek CamelCaseValue = iota
+	HTTPStatusOK
+	UserID2
+)
+
+func main() {
+	ck(DayOfWeek, "Day Of Week")
+	ck(HTTPStatusOK, "HTTP Status OK")
+	ck(UserID2, "User ID2")
+	ck(-127, "CamelCaseValue(-127)")
+}
+
+func ck(value CamelCaseValue, str string) {
+	if fmt.Sprint(value) != str {
+		panic("transform_title.go: " + str)
+	}
+}
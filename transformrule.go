@@ -0,0 +1,86 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"regexp"
+	"strings"
+	"text/template"
+)
+
+// nameRule is a user-defined step of the transformation of the names: either
+// a regular expression substitution, written s/regexp/replacement/ with any
+// delimiter after the s, or a text/template applied to the name, e.g.
+// {{ . | trimSuffix "Metric" | snake }}.
+type nameRule struct {
+	re   *regexp.Regexp
+	repl string
+	tmpl *template.Template
+}
+
+// parseNameRule parses a -transform-rule. The snake and kebab helpers of the
+// templates keep the acronyms whole.
+func parseNameRule(rule string, acronyms []string) (nameRule, error) {
+	if strings.Contains(rule, "{{") {
+		tmpl, err := template.New("rule").Funcs(template.FuncMap{
+			"snake":      func(s string) string { return strings.ToLower(delimit(s, '_', acronyms)) },
+			"kebab":      func(s string) string { return strings.ToLower(delimit(s, '-', acronyms)) },
+			"upper":      strings.ToUpper,
+			"lower":      strings.ToLower,
+			"trimPrefix": func(prefix, s string) string { return strings.TrimPrefix(s, prefix) },
+			"trimSuffix": func(suffix, s string) string { return strings.TrimSuffix(s, suffix) },
+		}).Option("missingkey=error").Parse(rule)
+		if err != nil {
+			return nameRule{}, err
+		}
+		return nameRule{tmpl: tmpl}, nil
+	}
+	if len(rule) < 2 || rule[0] != 's' {
+		return nameRule{}, fmt.Errorf("malformed rule %q: want s/regexp/replacement/ or a template", rule)
+	}
+	parts := strings.Split(rule[2:], rule[1:2])
+	if len(parts) != 3 || parts[2] != "" {
+		return nameRule{}, fmt.Errorf("malformed substitution %q: want s/regexp/replacement/", rule)
+	}
+	re, err := regexp.Compile(parts[0])
+	if err != nil {
+		return nameRule{}, err
+	}
+	return nameRule{re: re, repl: parts[1]}, nil
+}
+
+// apply returns the name transformed by the rule.
+func (r nameRule) apply(name string) (string, error) {
+	if r.re != nil {
+		return r.re.ReplaceAllString(name, r.repl), nil
+	}
+	var b bytes.Buffer
+	if err := r.tmpl.Execute(&b, name); err != nil {
+		return "", err
+	}
+	return b.String(), nil
+}
+
+// applyNameRules transforms the names of the values by the rules in order,
+// and fails if two values end up with the same name.
+func applyNameRules(values []Value, rules []nameRule, empty string) error {
+	owners := make(map[string]Value)
+	for i := range values {
+		name := values[i].name
+		for _, rule := range rules {
+			var err error
+			if name, err = rule.apply(name); err != nil {
+				return fmt.Errorf("%s: %s", values[i].constName, err)
+			}
+		}
+		if name == empty {
+			name = ""
+		}
+		if other, ok := owners[name]; ok && other.value != values[i].value {
+			return fmt.Errorf("%s and %s are both transformed to %q", other.constName, values[i].constName, name)
+		}
+		owners[name] = values[i]
+		values[i].name = name
+	}
+	return nil
+}
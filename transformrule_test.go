@@ -0,0 +1,44 @@
+// This file contains tests for the user-defined transformation of names.
+
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestBadNameRules(t *testing.T) {
+	for rule, expected := range map[string]string{
+		"x/a/b/":       "malformed rule",
+		"s/a/b":        "malformed substitution",
+		"s/a/b/c/":     "malformed substitution",
+		"s/(/b/":       "missing closing )",
+		"{{ nope . }}": `function "nope" not defined`,
+	} {
+		_, err := parseNameRule(rule, nil)
+		if err == nil || !strings.Contains(err.Error(), expected) {
+			t.Errorf("%s: got error %v; expected %q", rule, err, expected)
+		}
+	}
+}
+
+func TestNameRuleCollision(t *testing.T) {
+	rule, err := parseNameRule("s/^(Old|New)//", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	values := []Value{
+		{constName: "OldState", name: "OldState", value: 0},
+		{constName: "Current", name: "Current", value: 1},
+		{constName: "NewState", name: "NewState", value: 2},
+	}
+	err = applyNameRules(values, []nameRule{rule}, "")
+	if err == nil || err.Error() != `OldState and NewState are both transformed to "State"` {
+		t.Errorf("got error %v", err)
+	}
+	// Aliases may share their name.
+	values[2].value = 0
+	if err := applyNameRules(values, []nameRule{rule}, ""); err != nil {
+		t.Errorf("aliases: got error %v", err)
+	}
+}
@@ -0,0 +1,75 @@
+package main
+
+import (
+	"bytes"
+	jsonenc "encoding/json"
+	"fmt"
+	"strings"
+)
+
+// tsString returns s as a TypeScript string literal.
+func tsString(s string) string {
+	// A JSON string is a valid TypeScript string literal.
+	b, _ := jsonenc.Marshal(s)
+	return string(b)
+}
+
+// writeTSDoc writes doc, if any, as a JSDoc comment with the given indentation.
+func writeTSDoc(b *bytes.Buffer, indent, doc string) {
+	if doc == "" {
+		return
+	}
+	lines := strings.Split(strings.ReplaceAll(doc, "*/", "* /"), "\n")
+	if len(lines) == 1 {
+		fmt.Fprintf(b, "%s/** %s */\n", indent, lines[0])
+		return
+	}
+	fmt.Fprintf(b, "%s/**\n", indent)
+	for _, line := range lines {
+		fmt.Fprintf(b, "%s * %s\n", indent, line)
+	}
+	fmt.Fprintf(b, "%s */\n", indent)
+}
+
+// buildTypeScript writes the TypeScript definitions for the type: a union type
+// of the JSON names, an array of the values not deprecated and, if numericEnum
+// is set, an enum mirroring the Go values.
+func (g *Generator) buildTypeScript(runs [][]Value, typeName string, numericEnum bool) {
+	b := &g.tsBuf
+	fmt.Fprintf(b, "\n")
+	writeTSDoc(b, "", g.typeDoc(typeName))
+	fmt.Fprintf(b, "export type %s =\n", typeName)
+	set := g.nameSetFor("JSON")
+	var names, listed []string
+	for _, values := range runs {
+		for _, value := range values {
+			names = append(names, tsString(set.name(value)))
+			if !value.deprecated() {
+				listed = append(listed, tsString(set.name(value)))
+			}
+		}
+	}
+	for i, n := range names {
+		if i == len(names)-1 {
+			fmt.Fprintf(b, "  | %s;\n", n)
+		} else {
+			fmt.Fprintf(b, "  | %s\n", n)
+		}
+	}
+	fmt.Fprintf(b, "\nexport const %sValues: readonly %s[] = [\n", typeName, typeName)
+	for _, n := range listed {
+		fmt.Fprintf(b, "  %s,\n", n)
+	}
+	fmt.Fprintf(b, "];\n")
+	if !numericEnum {
+		return
+	}
+	fmt.Fprintf(b, "\nexport enum %sEnum {\n", typeName)
+	for _, values := range runs {
+		for _, value := range values {
+			writeTSDoc(b, "  ", value.doc)
+			fmt.Fprintf(b, "  %s = %s,\n", value.constName, value.str)
+		}
+	}
+	fmt.Fprintf(b, "}\n")
+}
@@ -8,6 +8,10 @@ package main
 
 import (
 	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
 )
 
@@ -54,7 +58,7 @@ Outer:
 	for n, test := range splitTests {
 		values := make([]Value, len(test.input))
 		for i, v := range test.input {
-			values[i] = Value{"", v, test.signed, fmt.Sprint(v), ""}
+			values[i] = Value{name: "", value: v, signed: test.signed, str: fmt.Sprint(v)}
 		}
 		runs := splitIntoRuns(values)
 		if len(runs) != len(test.output) {
@@ -75,3 +79,48 @@ Outer:
 		}
 	}
 }
+
+func TestParsePackageTags(t *testing.T) {
+	dir, err := ioutil.TempDir("", "stringer")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	files := map[string]string{
+		"go.mod":     "module tags\n",
+		"kind.go":    "package tags\n\ntype Kind int\n",
+		"plain.go":   "//go:build !extra\n\npackage tags\n\nconst Plain Kind = 1\n",
+		"extra.go":   "//go:build extra\n\npackage tags\n\nconst Extra Kind = 2\n",
+		"k_arm64.go": "package tags\n\nconst Arm Kind = 3\n",
+	}
+	for name, src := range files {
+		if err := ioutil.WriteFile(filepath.Join(dir, name), []byte(src), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// Load the package from inside its module.
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chdir(dir); err != nil {
+		t.Fatal(err)
+	}
+	defer os.Chdir(wd)
+	for _, test := range []struct {
+		tags     []string
+		platform string
+		names    string
+	}{
+		{nil, "linux/amd64", "Plain"},
+		{[]string{"extra"}, "linux/amd64", "Extra"},
+		{nil, "linux/arm64", "PlainArm"},
+	} {
+		var g Generator
+		g.parsePackage([]string{"."}, test.tags, test.platform)
+		g.generate("Kind", false, false, false, false, "noop", "", false, false, false, "")
+		if got := string(g.format()); !strings.Contains(got, fmt.Sprintf("_KindName = %q", test.names)) {
+			t.Errorf("tags %v on %s: expected names %s in\n%s", test.tags, test.platform, test.names, got)
+		}
+	}
+}
@@ -0,0 +1,106 @@
+package main
+
+import (
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/pascaldekloe/name"
+)
+
+// splitWords splits the name of a constant into words for the transforms.
+// Underscores, dashes, dots and spaces separate words, as do a change from
+// lower to upper case and the end of a run of capitals followed by lower case
+// letters, so that HTTPStatusOK splits into HTTP, Status and OK. Digits stay
+// with the word before them. The acronyms are words of their own wherever a
+// word starts, which settles runs of capitals, e.g. APIID, and mixed case
+// acronyms, e.g. GraphQL.
+func splitWords(s string, acronyms []string) []string {
+	// Try the longest acronyms first.
+	acronyms = append([]string(nil), acronyms...)
+	sort.SliceStable(acronyms, func(i, j int) bool { return len(acronyms[i]) > len(acronyms[j]) })
+
+	var words []string
+	for _, part := range strings.FieldsFunc(s, func(r rune) bool {
+		return r == '_' || r == '-' || r == '.' || unicode.IsSpace(r)
+	}) {
+		r := []rune(part)
+		for i := 0; i < len(r); {
+			j := i + acronymAt(r, i, acronyms)
+			if j == i {
+				j = i + 1
+				if unicode.IsUpper(r[i]) && j < len(r) && unicode.IsUpper(r[j]) {
+					for j < len(r) && unicode.IsUpper(r[j]) && acronymAt(r, j, acronyms) == 0 {
+						j++
+					}
+					if j < len(r) && unicode.IsLower(r[j]) {
+						// The last capital starts the next word.
+						j--
+					}
+				} else {
+					for j < len(r) && unicode.IsLower(r[j]) {
+						j++
+					}
+				}
+				for j < len(r) && unicode.IsDigit(r[j]) {
+					j++
+				}
+			}
+			words = append(words, string(r[i:j]))
+			i = j
+		}
+	}
+	return words
+}
+
+// nameWords returns the words of s for the transforms. Without acronyms,
+// they are the words of name.Delimit, as the transforms have always split
+// names; with acronyms, the ones of splitWords.
+func nameWords(s string, acronyms []string) []string {
+	if len(acronyms) == 0 {
+		return strings.Fields(name.Delimit(s, ' '))
+	}
+	return splitWords(s, acronyms)
+}
+
+// delimit returns the words of s joined by sep.
+func delimit(s string, sep rune, acronyms []string) string {
+	return strings.Join(nameWords(s, acronyms), string(sep))
+}
+
+// acronymAt returns the length of the acronym at r[i:], or 0 if none is a
+// word there: the acronym must not be followed by lower case letters, but for
+// the s of a plural, e.g. IDs.
+func acronymAt(r []rune, i int, acronyms []string) int {
+	// boundary reports whether no lower case letter is at j.
+	boundary := func(j int) bool { return j == len(r) || !unicode.IsLower(r[j]) }
+	for _, a := range acronyms {
+		n := len([]rune(a))
+		if i+n > len(r) || string(r[i:i+n]) != a {
+			continue
+		}
+		if boundary(i + n) {
+			return n
+		}
+		if r[i+n] == 's' && boundary(i+n+1) {
+			return n + 1
+		}
+	}
+	return 0
+}
+
+// capitalize returns the word with its first letter in upper case and the
+// others in lower case. Acronyms, and words in upper case, keep their case.
+func capitalize(word string, acronyms []string) string {
+	for _, a := range acronyms {
+		if word == a || word == a+"s" {
+			return word
+		}
+	}
+	if len([]rune(word)) > 1 && strings.ToUpper(word) == word {
+		return word
+	}
+	r := []rune(strings.ToLower(word))
+	r[0] = unicode.ToUpper(r[0])
+	return string(r)
+}
@@ -0,0 +1,76 @@
+// This file contains tests for the splitting of names into words.
+
+package main
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestSplitWords(t *testing.T) {
+	acronyms := []string{"HTTP", "API", "ID", "GraphQL", "IPv4"}
+	for _, test := range []struct {
+		name     string
+		acronyms []string
+		words    []string
+	}{
+		{"HTTPStatusOK", nil, []string{"HTTP", "Status", "OK"}},
+		{"DayOfWeek", nil, []string{"Day", "Of", "Week"}},
+		{"Day2Night", nil, []string{"Day2", "Night"}},
+		{"already_snake-kebab.dot space", nil, []string{"already", "snake", "kebab", "dot", "space"}},
+		{"lowerCamel", nil, []string{"lower", "Camel"}},
+		{"APIID", nil, []string{"APIID"}},
+		{"APIID", acronyms, []string{"API", "ID"}},
+		{"UserIDs", acronyms, []string{"User", "IDs"}},
+		{"GraphQLQuery", acronyms, []string{"GraphQL", "Query"}},
+		{"IPv4Addr", acronyms, []string{"IPv4", "Addr"}},
+		{"Identity", acronyms, []string{"Identity"}},
+		{"X", nil, []string{"X"}},
+	} {
+		if got := splitWords(test.name, test.acronyms); !reflect.DeepEqual(got, test.words) {
+			t.Errorf("%s: got %q; expected %q", test.name, got, test.words)
+		}
+	}
+}
+
+func TestDelimit(t *testing.T) {
+	acronyms := []string{"ID", "GraphQL"}
+	for _, test := range []struct {
+		name     string
+		acronyms []string
+		expected string
+	}{
+		// Without acronyms, the words are the ones of name.Delimit.
+		{"Day2Night", nil, "day2_night"},
+		{"UserIDs", nil, "user_I_ds"},
+		{"GraphQLQuery", nil, "graph_QL_query"},
+		{"UserIDs", acronyms, "User_IDs"},
+		{"GraphQLQuery", acronyms, "GraphQL_Query"},
+	} {
+		if got := delimit(test.name, '_', test.acronyms); got != test.expected {
+			t.Errorf("%s: got %q; expected %q", test.name, got, test.expected)
+		}
+	}
+}
+
+// TestTransformsSplitAlike checks that every transform splits a name into the
+// same words, with and without acronyms.
+func TestTransformsSplitAlike(t *testing.T) {
+	for _, acronyms := range [][]string{nil, {"ID", "GraphQL"}} {
+		g := Generator{acronyms: acronyms}
+		for _, name := range []string{"UserIDs", "GraphQLQuery", "Day2Night", "HTTPStatusOK"} {
+			words := make(map[string]string)
+			for _, transform := range []string{"snake", "dot", "space", "title", "train", "kebabu"} {
+				values := []Value{{name: name}}
+				g.applyTransform(values, transform, "")
+				words[transform] = strings.ToLower(strings.NewReplacer("_", " ", ".", " ", "-", " ").Replace(values[0].name))
+			}
+			for transform, w := range words {
+				if w != words["snake"] {
+					t.Errorf("%s with acronyms %q: %s splits into %q; snake into %q", name, acronyms, transform, w, words["snake"])
+				}
+			}
+		}
+	}
+}